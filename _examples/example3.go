@@ -15,7 +15,12 @@ func main() {
 	jobCh := make(chan int)
 	go func() {
 		for i := 0; ; i++ {
-			jobCh <- i
+			// Stop producing on shutdown, else we'd block forever (no consumers)
+			select {
+			case jobCh <- i:
+			case <-shutdown.C:
+				return
+			}
 		}
 	}()
 
@@ -43,6 +48,6 @@ func main() {
 	// Wait for a shutdown event (either signal or manual)
 	<-shutdown.C
 
-	// Wait for "important" goroutines
-	shutdown.Wg.Wait()
+	// Wait for "important" goroutines (and the shutdown hooks)
+	shutdown.Wait()
 }
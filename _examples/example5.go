@@ -29,7 +29,7 @@ func main() {
 	<-shutdown.C
 
 	// Wait for the worker to finish
-	shutdown.Wg.Wait()
+	shutdown.Wait()
 }
 
 var dbAdapter = &mockDB{}
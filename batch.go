@@ -0,0 +1,70 @@
+package shutdown
+
+import "context"
+
+// BatchRunner executes a list of work items one by one, and stops when
+// shutdown is initiated, checkpointing its progress.
+//
+// It is aimed at batch jobs (e.g. cron-style containers) that may get
+// preempted mid-run.
+type BatchRunner[T any] struct {
+	// Do executes a single work item. The Context of Manager is passed to it.
+	Do func(ctx context.Context, item T) error
+
+	// Checkpoint is an optional function called if the run is interrupted
+	// by a shutdown, with the completed and remaining items.
+	Checkpoint func(completed, remaining []T) error
+
+	// Manager is the Manager whose shutdown interrupts the run.
+	// If nil, the default Manager is used.
+	Manager *Manager
+}
+
+// Run executes items in order, until all are completed, Do returns an error,
+// or shutdown is initiated. It returns the completed and remaining items.
+//
+// If the run is interrupted by a shutdown (including Do returning an error
+// after shutdown has been initiated, e.g. because Context got cancelled),
+// Checkpoint is called (if provided), and its error is returned.
+// Else if Do returns an error, the run is stopped and the error is returned.
+// The interrupted or failed item is included in remaining.
+//
+// An interrupted run is not abandoned: the final Wait waits for the current
+// item and the checkpoint to complete.
+func (r *BatchRunner[T]) Run(items []T) (completed, remaining []T, err error) {
+	m := r.Manager
+	if m == nil {
+		m = std
+	}
+
+	done := make(chan struct{})
+	m.Go(func() {
+		defer close(done)
+		completed, remaining, err = r.run(m, items)
+	})
+	<-done
+	return
+}
+
+// run executes items in order, see Run.
+func (r *BatchRunner[T]) run(m *Manager, items []T) (completed, remaining []T, err error) {
+	for i, item := range items {
+		if !m.Initiated() {
+			err = r.Do(m.Context, item)
+			if err == nil {
+				continue
+			}
+		}
+
+		completed, remaining = items[:i], items[i:]
+		if m.Initiated() {
+			err = nil
+			if r.Checkpoint != nil {
+				err = r.Checkpoint(completed, remaining)
+			}
+		}
+		return
+	}
+
+	return items, nil, nil
+}
@@ -0,0 +1,105 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBatchRunner(t *testing.T) {
+	m := newTestManager()
+	var done []int
+	r := &BatchRunner[int]{
+		Do:      func(ctx context.Context, item int) error { done = append(done, item); return nil },
+		Manager: m,
+	}
+
+	completed, remaining, err := r.Run([]int{1, 2, 3})
+	if !reflect.DeepEqual(completed, []int{1, 2, 3}) || remaining != nil || err != nil {
+		t.Errorf("Run returned %v, %v, %v", completed, remaining, err)
+	}
+	if !reflect.DeepEqual(done, []int{1, 2, 3}) {
+		t.Errorf("done %v, want [1 2 3]", done)
+	}
+	m.Close()
+}
+
+func TestBatchRunnerError(t *testing.T) {
+	m := newTestManager()
+	errDo := errors.New("failed")
+	checkpointed := false
+	r := &BatchRunner[int]{
+		Do: func(ctx context.Context, item int) error {
+			if item == 2 {
+				return errDo
+			}
+			return nil
+		},
+		Checkpoint: func(completed, remaining []int) error { checkpointed = true; return nil },
+		Manager:    m,
+	}
+
+	completed, remaining, err := r.Run([]int{1, 2, 3})
+	if !reflect.DeepEqual(completed, []int{1}) || !reflect.DeepEqual(remaining, []int{2, 3}) || err != errDo {
+		t.Errorf("Run returned %v, %v, %v", completed, remaining, err)
+	}
+	if checkpointed {
+		t.Error("Checkpoint called without shutdown")
+	}
+	m.Close()
+}
+
+func TestBatchRunnerShutdown(t *testing.T) {
+	m := newTestManager()
+	errCheckpoint := errors.New("checkpoint failed")
+	var cpCompleted, cpRemaining []int
+	checkpointDone := false
+	r := &BatchRunner[int]{
+		Do: func(ctx context.Context, item int) error {
+			if item == 2 {
+				m.InitiateManual()
+				<-ctx.Done()
+				return ctx.Err() // Interrupted item.
+			}
+			return nil
+		},
+		Checkpoint: func(completed, remaining []int) error {
+			time.Sleep(20 * time.Millisecond)
+			cpCompleted, cpRemaining = completed, remaining
+			checkpointDone = true
+			return errCheckpoint
+		},
+		Manager: m,
+	}
+
+	runDone := make(chan struct{})
+	var completed, remaining []int
+	var err error
+	go func() {
+		defer close(runDone)
+		completed, remaining, err = r.Run([]int{1, 2, 3})
+	}()
+
+	<-m.C
+	m.Wait()
+	// The final Wait waits for the interrupted run and its checkpoint.
+	if !checkpointDone {
+		t.Error("final Wait returned before the checkpoint")
+	}
+	<-runDone
+
+	if !reflect.DeepEqual(completed, []int{1}) || !reflect.DeepEqual(remaining, []int{2, 3}) || err != errCheckpoint {
+		t.Errorf("Run returned %v, %v, %v", completed, remaining, err)
+	}
+	if !reflect.DeepEqual(cpCompleted, completed) || !reflect.DeepEqual(cpRemaining, remaining) {
+		t.Errorf("checkpoint of %v, %v", cpCompleted, cpRemaining)
+	}
+
+	// Runs after initiation don't execute any items.
+	completed, remaining, err = r.Run([]int{4})
+	if len(completed) != 0 || !reflect.DeepEqual(remaining, []int{4}) {
+		t.Errorf("Run after shutdown returned %v, %v, %v", completed, remaining, err)
+	}
+}
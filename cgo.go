@@ -0,0 +1,93 @@
+package shutdown
+
+import (
+	"os"
+	"time"
+)
+
+// WithCgoExitTimeout sets the max time a forced exit waits for running hooks
+// of PhaseCgo to complete (see OnCgoCleanup). The default is 5 seconds.
+func WithCgoExitTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.cgoExitTimeout = timeout
+	}
+}
+
+// OnCgoCleanup registers f as a hook of PhaseCgo, for releasing resources of
+// C libraries used through cgo. Hooks of PhaseCgo are critical (see WithCritical),
+// and they are never run concurrently with or after an exit:
+//   - once a forced exit (e.g. by escalation or the watchdog) has begun, or the
+//     final Wait has given up waiting (see WithGraceTimeout), PhaseCgo is not
+//     started anymore,
+//   - if PhaseCgo is running, a forced exit waits for it to complete (no longer
+//     than the cgo exit timeout, see WithCgoExitTimeout), and so does the
+//     final Wait.
+//
+// The hard kill timer (see WithHardKill) is the only exception: as a last line
+// of defense, it exits without waiting for PhaseCgo.
+//
+// This avoids use-after-free errors in C libraries caused by the process
+// exiting in the middle of their cleanup.
+func OnCgoCleanup(f func(), opts ...HookOption) { std.OnCgoCleanup(f, opts...) }
+
+// OnCgoCleanup registers f as a hook of PhaseCgo. See the package-level OnCgoCleanup.
+func (m *Manager) OnCgoCleanup(f func(), opts ...HookOption) {
+	m.OnPhase(PhaseCgo, f, append(opts[:len(opts):len(opts)], WithCritical())...)
+}
+
+// beginCgo marks PhaseCgo as running. It returns false if an exit has begun,
+// in which case PhaseCgo must not be run.
+func (m *Manager) beginCgo() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.exiting {
+		return false
+	}
+	m.cgoDone = make(chan struct{})
+	return true
+}
+
+// endCgo marks PhaseCgo as completed.
+func (m *Manager) endCgo() {
+	m.mu.Lock()
+	close(m.cgoDone)
+	m.mu.Unlock()
+}
+
+// beginExit marks the exit as begun, so PhaseCgo can't be started anymore,
+// and waits for PhaseCgo to complete if it is running (no longer than m.cgoExitTimeout).
+func (m *Manager) beginExit() {
+	m.mu.Lock()
+	m.exiting = true
+	cgoDone := m.cgoDone
+	m.mu.Unlock()
+
+	if cgoDone == nil {
+		return
+	}
+	select {
+	case <-cgoDone:
+		return
+	default:
+	}
+
+	m.logf("Waiting for cgo cleanup before exiting...")
+	t := time.NewTimer(m.cgoExitTimeout)
+	defer t.Stop()
+
+	select {
+	case <-cgoDone:
+	case <-t.C:
+		m.warnf("Cgo cleanup did not complete within %v.", m.cgoExitTimeout)
+	}
+}
+
+// exit begins the exit (see beginExit), calls the last-gasp function
+// (see SetLastGasp), and exits the app with code. reason is passed to the
+// last-gasp function.
+func (m *Manager) exit(code int, reason string) {
+	m.beginExit()
+	m.callLastGasp(reason)
+	os.Exit(code)
+}
@@ -0,0 +1,83 @@
+package shutdown
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCgoCleanupOrder(t *testing.T) {
+	m := newTestManager()
+	r := &recorder{}
+	m.OnCgoCleanup(r.addFunc("cgo"))
+	m.OnPhase(PhaseCleanup, r.addFunc("cleanup"))
+	m.OnShutdown(r.addFunc("stop"))
+	m.Close()
+
+	if got, want := r.String(), "stop,cleanup,cgo"; got != want {
+		t.Errorf("events are %q, want %q", got, want)
+	}
+}
+
+func TestCgoCleanupOptsNotModified(t *testing.T) {
+	m := newTestManager()
+	opts := make([]HookOption, 1, 2)
+	opts[0] = WithName("sdk")
+	m.OnCgoCleanup(func() {}, opts...)
+
+	// The caller's backing array must not be written to.
+	if opts = opts[:2]; opts[1] != nil {
+		t.Error("OnCgoCleanup wrote into the backing array of opts")
+	}
+	m.Close()
+}
+
+func TestCgoCleanupSkippedAfterExitBegun(t *testing.T) {
+	m := newTestManager()
+	ran := false
+	m.OnCgoCleanup(func() { ran = true })
+	m.beginExit()
+	m.Close()
+
+	if ran {
+		t.Error("cgo hook run after the exit has begun")
+	}
+}
+
+func TestCgoCleanupWaitedForByExit(t *testing.T) {
+	m := newTestManager()
+	started, finished := make(chan struct{}), make(chan struct{})
+	m.OnCgoCleanup(func() {
+		close(started)
+		time.Sleep(30 * time.Millisecond)
+		close(finished)
+	})
+	m.InitiateManual()
+	<-started
+
+	m.beginExit() // E.g. a forced exit: waits for PhaseCgo.
+	select {
+	case <-finished:
+	default:
+		t.Error("beginExit returned while the cgo hook was running")
+	}
+	m.Wait()
+}
+
+func TestCgoExitTimeout(t *testing.T) {
+	m := newTestManager(WithCgoExitTimeout(20 * time.Millisecond))
+	started, release := make(chan struct{}), make(chan struct{})
+	m.OnCgoCleanup(func() {
+		close(started)
+		<-release
+	})
+	m.InitiateManual()
+	<-started
+
+	start := time.Now()
+	m.beginExit()
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("beginExit waited %v for a stuck cgo hook, want the cgo exit timeout", d)
+	}
+	close(release)
+	m.Wait()
+}
@@ -0,0 +1,50 @@
+package shutdown
+
+import "context"
+
+// Send sends v on ch. It aborts if shutdown is initiated, returning
+// ErrInitiated, or if ctx is done, returning ctx.Err().
+//
+// Use it instead of a plain send to avoid goroutines leaking blocked on a
+// channel send during shutdown (whose receivers have already exited).
+func Send[T any](ctx context.Context, ch chan<- T, v T) error {
+	return SendOn(ctx, std, ch, v)
+}
+
+// SendOn is like Send, but it aborts when the shutdown managed by m is initiated.
+func SendOn[T any](ctx context.Context, m *Manager, ch chan<- T, v T) error {
+	if m.Initiated() {
+		return ErrInitiated
+	}
+	select {
+	case ch <- v:
+		return nil
+	case <-m.C:
+		return ErrInitiated
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Recv receives a value from ch. ok reports whether the value was sent
+// on ch (false if ch is closed), like in a plain receive.
+// It aborts if shutdown is initiated, returning ErrInitiated, or if ctx is
+// done, returning ctx.Err().
+func Recv[T any](ctx context.Context, ch <-chan T) (v T, ok bool, err error) {
+	return RecvOn(ctx, std, ch)
+}
+
+// RecvOn is like Recv, but it aborts when the shutdown managed by m is initiated.
+func RecvOn[T any](ctx context.Context, m *Manager, ch <-chan T) (v T, ok bool, err error) {
+	if m.Initiated() {
+		return v, false, ErrInitiated
+	}
+	select {
+	case v, ok = <-ch:
+		return v, ok, nil
+	case <-m.C:
+		return v, false, ErrInitiated
+	case <-ctx.Done():
+		return v, false, ctx.Err()
+	}
+}
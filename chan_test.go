@@ -0,0 +1,67 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSendRecv(t *testing.T) {
+	m := newTestManager()
+	ctx := context.Background()
+	ch := make(chan int, 1)
+
+	if err := SendOn(ctx, m, ch, 1); err != nil {
+		t.Errorf("SendOn returned %v, want nil", err)
+	}
+	if v, ok, err := RecvOn(ctx, m, ch); v != 1 || !ok || err != nil {
+		t.Errorf("RecvOn returned %d, %t, %v, want 1, true, nil", v, ok, err)
+	}
+	close(ch)
+	if _, ok, err := RecvOn(ctx, m, ch); ok || err != nil {
+		t.Errorf("RecvOn on closed channel returned %t, %v, want false, nil", ok, err)
+	}
+
+	// ctx done.
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := SendOn(cctx, m, make(chan int), 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("SendOn returned %v, want %v", err, context.Canceled)
+	}
+	if _, _, err := RecvOn(cctx, m, make(chan int)); !errors.Is(err, context.Canceled) {
+		t.Errorf("RecvOn returned %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestSendRecvUnblockOnShutdown(t *testing.T) {
+	m := newTestManager()
+	ctx := context.Background()
+
+	sendErr, recvErr := make(chan error, 1), make(chan error, 1)
+	go func() { sendErr <- SendOn(ctx, m, make(chan int), 1) }()
+	go func() {
+		_, _, err := RecvOn(ctx, m, make(chan int))
+		recvErr <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	m.InitiateManual()
+
+	for name, ch := range map[string]chan error{"SendOn": sendErr, "RecvOn": recvErr} {
+		select {
+		case err := <-ch:
+			if !errors.Is(err, ErrInitiated) {
+				t.Errorf("%s returned %v, want %v", name, err, ErrInitiated)
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("%s not unblocked by shutdown", name)
+		}
+	}
+
+	// After initiation, they return right away even if ch is ready.
+	ready := make(chan int, 1)
+	if err := SendOn(ctx, m, ready, 1); !errors.Is(err, ErrInitiated) {
+		t.Errorf("SendOn after initiation returned %v, want %v", err, ErrInitiated)
+	}
+	m.Wait()
+}
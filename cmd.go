@@ -0,0 +1,80 @@
+package shutdown
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// errNoJobObjects is returned by newCmdJob on platforms without job objects.
+var errNoJobObjects = errors.New("job objects are only supported on Windows")
+
+// WithJobObject makes StartCmd place each managed child process in a Windows
+// job object configured to kill-on-close. On shutdown the whole job (the child
+// and the processes it started) is terminated, and since the job handle is held
+// by the app, even a forced exit (or a crash) of the app kills the job, so no
+// orphaned children are leaked. Processes started by the child before it is
+// placed in the job (right after it is started) are not part of the job.
+//
+// WithJobObject has no effect on other platforms.
+func WithJobObject() Option {
+	return func(m *Manager) {
+		m.jobObject = true
+	}
+}
+
+// StartCmd starts cmd as a managed child process. On Unix it is started in its
+// own process group, and when shutdown is initiated, SIGTERM is sent to the whole
+// group, so grandchildren (e.g. shell pipelines) also receive it instead of
+// getting orphaned. On Windows, with WithJobObject, the job object of the child
+// is terminated. On other platforms the child process is killed on shutdown.
+//
+// cmd.Wait is called by StartCmd (so callers must not call it); its result is
+// sent on the returned channel, after the child has exited.
+func StartCmd(cmd *exec.Cmd) (<-chan error, error) { return std.StartCmd(cmd) }
+
+// StartCmd starts cmd as a managed child process, terminated on shutdown.
+// See the package-level StartCmd.
+func (m *Manager) StartCmd(cmd *exec.Cmd) (<-chan error, error) {
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	var job *cmdJob
+	if m.jobObject {
+		var err error
+		if job, err = newCmdJob(cmd); err != nil {
+			m.errorf("Failed to place child process %d in a job object: %v", cmd.Process.Pid, err)
+		}
+	}
+
+	waitCh := make(chan error, 1)
+	go func() {
+		waitCh <- cmd.Wait()
+	}()
+
+	doneCh := make(chan error, 1)
+	m.Go(func() {
+		if job != nil {
+			defer job.close()
+		}
+
+		select {
+		case err := <-waitCh:
+			doneCh <- err
+			return
+		case <-m.C:
+		}
+
+		terminate := terminateProcessGroup
+		if job != nil {
+			terminate = func(*exec.Cmd) error { return job.terminate() }
+		}
+		if err := terminate(cmd); err != nil {
+			m.errorf("Failed to terminate child process %d: %v", cmd.Process.Pid, err)
+		}
+		doneCh <- <-waitCh
+	})
+
+	return doneCh, nil
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package shutdown
+
+import "os/exec"
+
+// cmdJob is a job object holding a managed child process. Job objects are
+// only supported on Windows.
+type cmdJob struct{}
+
+// newCmdJob returns an error: job objects are only supported on Windows.
+func newCmdJob(cmd *exec.Cmd) (*cmdJob, error) {
+	return nil, errNoJobObjects
+}
+
+// terminate is a no-op.
+func (j *cmdJob) terminate() error { return nil }
+
+// close is a no-op.
+func (j *cmdJob) close() {}
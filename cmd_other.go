@@ -0,0 +1,13 @@
+//go:build !unix
+
+package shutdown
+
+import "os/exec"
+
+// setProcessGroup is a no-op: process groups are only supported on Unix.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminateProcessGroup kills the process of cmd.
+func terminateProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
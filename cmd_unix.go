@@ -0,0 +1,22 @@
+//go:build unix
+
+package shutdown
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup makes cmd start in its own process group.
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// terminateProcessGroup sends SIGTERM to the process group of cmd.
+func terminateProcessGroup(cmd *exec.Cmd) error {
+	// Negative pid signals the whole process group.
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+}
@@ -0,0 +1,63 @@
+//go:build unix
+
+package shutdown
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStartCmd(t *testing.T) {
+	m := newTestManager()
+	doneCh, err := m.StartCmd(exec.Command("sh", "-c", "exit 3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exitErr *exec.ExitError
+	if err := <-doneCh; !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("child exited with %v, want exit status 3", err)
+	}
+	m.Close()
+
+	if _, err := m.StartCmd(exec.Command("/nonexistent")); err == nil {
+		t.Error("StartCmd of a nonexistent command succeeded")
+	}
+}
+
+func TestStartCmdProcessGroup(t *testing.T) {
+	m := newTestManager()
+	marker := filepath.Join(t.TempDir(), "terminated")
+
+	// The grandchild records receiving SIGTERM.
+	script := `(trap "echo > $MARKER; exit 0" TERM; while :; do sleep 0.01; done) & wait`
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Env = append(os.Environ(), "MARKER="+marker)
+	doneCh, err := m.StartCmd(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	m.Close()
+	select {
+	case <-doneCh:
+	default:
+		t.Error("final Wait returned before the child exited")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(marker); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("grandchild did not receive SIGTERM")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
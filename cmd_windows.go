@@ -0,0 +1,110 @@
+package shutdown
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                     = syscall.NewLazyDLL("kernel32.dll")
+	procCreateJobObjectW         = kernel32.NewProc("CreateJobObjectW")
+	procSetInformationJobObject  = kernel32.NewProc("SetInformationJobObject")
+	procAssignProcessToJobObject = kernel32.NewProc("AssignProcessToJobObject")
+	procTerminateJobObject       = kernel32.NewProc("TerminateJobObject")
+)
+
+// Constants of the Windows job object API.
+const (
+	jobObjectExtendedLimitInformationClass = 9
+	jobObjectLimitKillOnJobClose           = 0x2000
+	processSetQuota                        = 0x0100
+	processTerminate                       = 0x0001
+)
+
+// jobObjectBasicLimitInformation is JOBOBJECT_BASIC_LIMIT_INFORMATION.
+type jobObjectBasicLimitInformation struct {
+	PerProcessUserTimeLimit int64
+	PerJobUserTimeLimit     int64
+	LimitFlags              uint32
+	MinimumWorkingSetSize   uintptr
+	MaximumWorkingSetSize   uintptr
+	ActiveProcessLimit      uint32
+	Affinity                uintptr
+	PriorityClass           uint32
+	SchedulingClass         uint32
+}
+
+// ioCounters is IO_COUNTERS.
+type ioCounters struct {
+	ReadOperationCount  uint64
+	WriteOperationCount uint64
+	OtherOperationCount uint64
+	ReadTransferCount   uint64
+	WriteTransferCount  uint64
+	OtherTransferCount  uint64
+}
+
+// jobObjectExtendedLimitInformation is JOBOBJECT_EXTENDED_LIMIT_INFORMATION.
+type jobObjectExtendedLimitInformation struct {
+	BasicLimitInformation jobObjectBasicLimitInformation
+	_                     [8 - unsafe.Sizeof(uintptr(0))]byte // IO_COUNTERS is 8-byte aligned on 32-bit too
+	IoInfo                ioCounters
+	ProcessMemoryLimit    uintptr
+	JobMemoryLimit        uintptr
+	PeakProcessMemoryUsed uintptr
+	PeakJobMemoryUsed     uintptr
+}
+
+// cmdJob is a kill-on-close job object holding a managed child process
+// (and the processes it starts).
+type cmdJob struct {
+	h syscall.Handle
+}
+
+// newCmdJob creates a kill-on-close job object, and assigns the started
+// process of cmd to it. The job handle is held by the current process, so
+// when it exits for any reason, all processes of the job are killed.
+func newCmdJob(cmd *exec.Cmd) (*cmdJob, error) {
+	r, _, err := procCreateJobObjectW.Call(0, 0)
+	if r == 0 {
+		return nil, fmt.Errorf("create job object: %w", err)
+	}
+	j := &cmdJob{h: syscall.Handle(r)}
+
+	info := jobObjectExtendedLimitInformation{}
+	info.BasicLimitInformation.LimitFlags = jobObjectLimitKillOnJobClose
+	r, _, err = procSetInformationJobObject.Call(uintptr(j.h), jobObjectExtendedLimitInformationClass,
+		uintptr(unsafe.Pointer(&info)), unsafe.Sizeof(info))
+	if r == 0 {
+		j.close()
+		return nil, fmt.Errorf("set job object information: %w", err)
+	}
+
+	ph, err := syscall.OpenProcess(processSetQuota|processTerminate, false, uint32(cmd.Process.Pid))
+	if err != nil {
+		j.close()
+		return nil, fmt.Errorf("open process: %w", err)
+	}
+	defer syscall.CloseHandle(ph)
+
+	if r, _, err = procAssignProcessToJobObject.Call(uintptr(j.h), uintptr(ph)); r == 0 {
+		j.close()
+		return nil, fmt.Errorf("assign process to job object: %w", err)
+	}
+	return j, nil
+}
+
+// terminate kills all processes of the job.
+func (j *cmdJob) terminate() error {
+	if r, _, err := procTerminateJobObject.Call(uintptr(j.h), 1); r == 0 {
+		return err
+	}
+	return nil
+}
+
+// close closes the job handle (killing remaining processes of the job).
+func (j *cmdJob) close() {
+	syscall.CloseHandle(j.h)
+}
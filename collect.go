@@ -0,0 +1,45 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+)
+
+// Collect runs the given workers concurrently, passing Context to them, and
+// gathers their results.
+//
+// Workers should return ASAP (with their partial results) when Context is
+// cancelled.
+//
+// Collect blocks until all workers return. The returned slice holds the result
+// of each worker at the same index, even if the worker also returned an error
+// (so partial results are not lost). The returned error is the first non-nil
+// error in worker order.
+func Collect[T any](workers ...func(ctx context.Context) (T, error)) ([]T, error) {
+	return CollectOn(std, workers...)
+}
+
+// CollectOn is like Collect, but it passes the Context of m to the workers,
+// and registers them in the Wg of m.
+func CollectOn[T any](m *Manager, workers ...func(ctx context.Context) (T, error)) ([]T, error) {
+	results := make([]T, len(workers))
+	errs := make([]error, len(workers))
+
+	wg := &sync.WaitGroup{}
+	for i, worker := range workers {
+		i, worker := i, worker
+		wg.Add(1)
+		m.Go(func() {
+			defer wg.Done()
+			results[i], errs[i] = worker(m.Context)
+		})
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,60 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCollect(t *testing.T) {
+	m := newTestManager()
+	err1, err2 := errors.New("err1"), errors.New("err2")
+
+	results, err := CollectOn(m,
+		func(ctx context.Context) (int, error) { time.Sleep(20 * time.Millisecond); return 1, nil },
+		func(ctx context.Context) (int, error) { time.Sleep(10 * time.Millisecond); return 2, err1 },
+		func(ctx context.Context) (int, error) { return 3, err2 },
+	)
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(results, want) {
+		t.Errorf("results are %v, want %v", results, want)
+	}
+	if err != err1 {
+		t.Errorf("err is %v, want the first error in worker order (%v)", err, err1)
+	}
+	m.Close()
+}
+
+func TestCollectShutdown(t *testing.T) {
+	m := newTestManager()
+	worker := func(ctx context.Context) (int, error) {
+		n := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return n, ctx.Err() // Partial result.
+			case <-time.After(time.Millisecond):
+				n++
+			}
+		}
+	}
+
+	time.AfterFunc(50*time.Millisecond, m.InitiateManual)
+	results, err := CollectOn(m, worker, worker)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err is %v, want %v", err, context.Canceled)
+	}
+	for i, n := range results {
+		if n == 0 {
+			t.Errorf("partial result of worker #%d is lost", i)
+		}
+	}
+
+	// After the final Wait, workers are run in the caller's goroutine.
+	m.Wait()
+	results, err = CollectOn(m, func(ctx context.Context) (int, error) { return 1, ctx.Err() })
+	if len(results) != 1 || results[0] != 1 || !errors.Is(err, context.Canceled) {
+		t.Errorf("after the final Wait got %v, %v", results, err)
+	}
+}
@@ -0,0 +1,60 @@
+package shutdown
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// consoleLogger is a Logger printing concise, leveled lines for CLIs,
+// see NewConsoleLogger.
+type consoleLogger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	color bool
+}
+
+// NewConsoleLogger returns a Logger for interactive terminals of CLI tools,
+// to be used with WithLogger. It writes concise lifecycle lines to w (without
+// timestamps), each prefixed with its level (see Level): "shutdown:" for
+// progress and milestones, "shutdown warning:" for timeouts and ignored events, and
+// "shutdown error:" for panics, failures and forced exits. If color is true,
+// lines are colorized with ANSI escape codes by level.
+//
+// The returned Logger implements LevelLogger. Lines logged with Printf are
+// info lines.
+func NewConsoleLogger(w io.Writer, color bool) Logger {
+	return &consoleLogger{w: w, color: color}
+}
+
+// consoleLevels holds the prefixes and colors (ANSI escape codes) of levels.
+var consoleLevels = map[Level]struct{ prefix, color string }{
+	LevelInfo:   {"shutdown:", "\x1b[36m"},
+	LevelNotice: {"shutdown:", "\x1b[36m"},
+	LevelWarn:   {"shutdown warning:", "\x1b[33m"},
+	LevelError:  {"shutdown error:", "\x1b[31m"},
+}
+
+// Printf implements Logger, logging an info line.
+func (l *consoleLogger) Printf(format string, v ...any) {
+	l.Logf(LevelInfo, format, v...)
+}
+
+// Logf implements LevelLogger.
+func (l *consoleLogger) Logf(level Level, format string, v ...any) {
+	msg := strings.TrimSuffix(fmt.Sprintf(format, v...), "\n")
+	lvl, ok := consoleLevels[level]
+	if !ok {
+		lvl = consoleLevels[LevelInfo]
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.color {
+		fmt.Fprintf(l.w, "%s%s\x1b[0m %s\n", lvl.color, lvl.prefix, msg)
+	} else {
+		fmt.Fprintf(l.w, "%s %s\n", lvl.prefix, msg)
+	}
+}
@@ -0,0 +1,47 @@
+package shutdown
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConsoleLogger(t *testing.T) {
+	sb := &strings.Builder{}
+	l := NewConsoleLogger(sb, false).(LevelLogger)
+	l.Printf("Manual shutdown initiated: %s...", "failed deploy")
+	l.Logf(LevelWarn, "Leadership resignation timed out.")
+	l.Logf(LevelError, "Panic in %s: %v", "hook", errors.New("boom"))
+
+	want := "shutdown: Manual shutdown initiated: failed deploy...\n" +
+		"shutdown warning: Leadership resignation timed out.\n" +
+		"shutdown error: Panic in hook: boom\n"
+	if got := sb.String(); got != want {
+		t.Errorf("output is %q, want %q", got, want)
+	}
+
+	sb.Reset()
+	NewConsoleLogger(sb, true).(LevelLogger).Logf(LevelWarn, "Shutdown aborted.")
+	if got, want := sb.String(), "\x1b[33mshutdown warning:\x1b[0m Shutdown aborted.\n"; got != want {
+		t.Errorf("colored output is %q, want %q", got, want)
+	}
+}
+
+func TestConsoleLoggerLevels(t *testing.T) {
+	sb := &strings.Builder{}
+	m := newTestManager(WithLogger(NewConsoleLogger(sb, false)))
+	m.OnShutdown(func() { panic("boom") }, WithName("panicking"))
+	m.OnShutdown(func() { select {} }, WithName("stuck"), WithTimeout(time.Millisecond))
+	m.Close()
+
+	for _, want := range []string{
+		"shutdown: Shutdown initiated by Close...",
+		"shutdown error: Shutdown hook panicking panicked: boom",
+		"shutdown warning: Shutdown hook stuck timed out after 1ms, moving on.",
+	} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("%q not logged, output:\n%s", want, sb)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package shutdown
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"time"
+)
+
+// WithQuitDiagnostics makes SIGQUIT initiate a graceful shutdown, after dumping
+// the stacks of all goroutines to the logger (see WithLogger). Useful for
+// debugging shutdowns that don't complete (e.g. a goroutine never returns).
+//
+// SIGQUIT stays subscribed to until the final Wait returns: if shutdown has
+// already been initiated (e.g. by SIGTERM) and the drain is stuck, SIGQUIT
+// dumps the stacks again without exiting, so the stuck goroutines can be found.
+//
+// Without this option SIGQUIT keeps its default behavior: the runtime dumps
+// the stacks and exits immediately. The option has no effect on non-Unix
+// platforms, which have no SIGQUIT.
+func WithQuitDiagnostics() Option {
+	return func(m *Manager) {
+		m.quitDiagnostics = true
+	}
+}
+
+// stackDumpSource is a signal source dumping all goroutine stacks when
+// the signal is received.
+type stackDumpSource struct {
+	*signalSource
+}
+
+// Wait implements Source.
+func (s stackDumpSource) Wait(ctx context.Context) bool {
+	if !s.signalSource.Wait(ctx) {
+		return false
+	}
+	s.m.noticef("Goroutine stacks:\n%s", allStacks())
+	return true
+}
+
+// allStacks returns the stacks of all goroutines.
+func allStacks() []byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// subscribeQuit subscribes to SIGQUIT for diagnostics, if not yet subscribed.
+func (m *Manager) subscribeQuit() {
+	if m.quitSubscribed || quitSignal == nil {
+		return
+	}
+	m.quitSubscribed = true
+
+	src := newSignalSource(m, quitSignal)
+	// Keep dumping stacks on SIGQUIT during the shutdown, until it completes.
+	src.force = func(os.Signal) {
+		m.noticef("Goroutine stacks (shutdown in progress):\n%s", allStacks())
+	}
+	go func() {
+		<-m.waitDone
+		src.unsubscribe()
+	}()
+	m.AddSource(stackDumpSource{src})
+}
+
+// WithBlockingThreshold sets the time after which a warning (with the stacks of
+// all goroutines) is logged if a user callback called on an internal goroutine
+// that must stay responsive is still running: leadership resignations (called
+// before broadcasting shutdown), reload hooks and escalation schedule steps.
+// The default is 1 second, 0 disables the warnings.
+func WithBlockingThreshold(threshold time.Duration) Option {
+	return func(m *Manager) {
+		m.blockingThreshold = threshold
+	}
+}
+
+// warnBlocking logs a warning if the callback named name is still running after
+// the blocking threshold. The returned function must be called when the callback returns.
+func (m *Manager) warnBlocking(name string) (done func()) {
+	threshold := m.blockingThreshold
+	if threshold <= 0 {
+		return func() {}
+	}
+
+	t := time.AfterFunc(threshold, func() {
+		m.warnf("Callback %s is blocking for more than %v, goroutine stacks:\n%s", name, threshold, allStacks())
+	})
+	return func() { t.Stop() }
+}
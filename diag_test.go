@@ -0,0 +1,33 @@
+package shutdown
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBlockingThreshold(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		resign   time.Duration
+		wantWarn bool
+	}{
+		{"over threshold", 100 * time.Millisecond, true},
+		{"under threshold", 0, false},
+	} {
+		l := &logRecorder{}
+		m := New(WithLogger(l), WithBlockingThreshold(30*time.Millisecond))
+		m.AddLeadership("lease", func(ctx context.Context) error {
+			time.Sleep(c.resign)
+			return nil
+		})
+		m.Close()
+		time.Sleep(50 * time.Millisecond) // A late warning would be logged by now.
+
+		warned := strings.Contains(l.String(), "Callback %s is blocking")
+		if warned != c.wantWarn {
+			t.Errorf("%s: warned: %t, want %t (logs: %s)", c.name, warned, c.wantWarn, l.String())
+		}
+	}
+}
@@ -0,0 +1,39 @@
+//go:build unix
+
+package shutdown
+
+import (
+	"log"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestQuitDiagnosticsDuringDrain(t *testing.T) {
+	if inChild(t) {
+		m := New(WithSignals(syscall.SIGTERM), WithQuitDiagnostics(), WithLogger(log.New(os.Stdout, "", 0)))
+		stuck := make(chan struct{})
+		m.Go(func() {
+			<-m.C
+			<-stuck // A stuck drain.
+		})
+
+		raise(syscall.SIGTERM)
+		<-m.C
+		raise(syscall.SIGQUIT) // Must dump stacks, not exit.
+		time.Sleep(200 * time.Millisecond)
+		close(stuck)
+		m.Wait()
+		os.Exit(0)
+	}
+
+	code, out := runChild(t)
+	if code != 0 {
+		t.Errorf("child exited with %d, want 0, output:\n%s", code, out)
+	}
+	if !strings.Contains(out, "Goroutine stacks (shutdown in progress):") {
+		t.Errorf("stacks not dumped on SIGQUIT during the drain, output:\n%s", out)
+	}
+}
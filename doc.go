@@ -2,7 +2,10 @@
 Package shutdown helps controlling app shutdown and graceful termination of goroutines.
 
 It listens for SIGTERM (e.g. kill command) and SIGINT (e.g. CTRL+C) signals,
-and also provides a manual way to trigger shutdown.
+and also provides a manual way to trigger shutdown. Additional sources that may
+initiate a shutdown (e.g. an admin API) can be added using AddSource().
+Reload is opt-in: once ReloadC() or OnReload() is used, SIGHUP triggers a reload
+instead of terminating the app.
 
 It publishes a single, shared shutdown channel which is closed when shutdown
 is about to happen. Modules (goroutines) should monitor this channel
@@ -15,8 +18,28 @@ Background tasks requiring a context may use this directly or as a parent contex
 
 It also publishes a WaitGroup goroutines may use to "register" themselves
 should they wish to be patiently waited for and not get terminated abruptly.
-For this to "work", this shared WaitGroup must be "waited for"
-in the main() function before returning.
+For this to "work", the final Wait() (or Exit()) must be called in the main()
+function before returning. Besides the shared WaitGroup, it also waits for
+critical sections and shuts down servers registered with ManageLast(), so
+calling Wg.Wait() directly is not enough.
+
+The goroutines started by the helpers of the package (e.g. Watch(), Poll(),
+Collect(), AfterFunc(), Manage(), StartCmd()) and the shutdown hooks are
+registered in the WaitGroup (helpers use Go()), so the final Wait() waits for
+them without any extra bookkeeping.
+
+Cleanup code may also be registered from anywhere in the app using OnShutdown().
+Registered hooks are run when shutdown is initiated, and they are also waited for
+by the final Wait(). Larger apps may attach hooks to named phases
+(drain, stop, cleanup) using OnPhase(): phases are executed sequentially, while
+hooks within a phase are run concurrently. OnPhaseStart() and OnPhaseEnd()
+register callbacks around phases (e.g. for metrics).
+
+The package-level functions and variables operate on a default shutdown Manager.
+Independent shutdown scopes (e.g. in tests, or for embedded components) can be
+created using New(), having the same API as methods (generic helpers have
+variants taking the Manager, e.g. CollectOn()). They handle no signals by default,
+and Close() shuts them down and releases their resources.
 
 # Simple example
 
@@ -53,7 +76,12 @@ This app also self-terminates after 10 seconds:
 		jobCh := make(chan int)
 		go func() {
 			for i := 0; ; i++ {
-				jobCh <- i
+				// Stop producing on shutdown, else we'd block forever (no consumers)
+				select {
+				case jobCh <- i:
+				case <-shutdown.C:
+					return
+				}
 			}
 		}()
 
@@ -79,8 +107,8 @@ This app also self-terminates after 10 seconds:
 		// Wait for a shutdown event (either signal or manual)
 		<-shutdown.C
 
-		// Wait for "important" goroutines
-		shutdown.Wg.Wait()
+		// Wait for "important" goroutines (and the shutdown hooks)
+		shutdown.Wait()
 	}
 
 Note that the above worker goroutine does not guarantee that it won't start execution
@@ -188,7 +216,7 @@ The following example launches a background worker doing something that uses / r
 		<-shutdown.C
 
 		// Wait for the worker to finish
-		shutdown.Wg.Wait()
+		shutdown.Wait()
 	}
 */
 package shutdown
@@ -0,0 +1,150 @@
+package shutdown
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DrainTime is the time a task or a hook took to drain, see DrainTimes.
+type DrainTime struct {
+	// Name identifies the task or hook: the package-qualified function and
+	// the location of the Go call that started the task
+	// (e.g. "example.com/app/worker.Start (worker.go:42)"), or the name of
+	// the hook (see WithName).
+	Name string
+
+	// Hook tells if this is the run time of a hook (as opposed to a task).
+	Hook bool
+
+	// Duration is the time elapsed between cancelling Context and the task
+	// returning, or the time the hook took to run.
+	Duration time.Duration
+}
+
+// DrainTimes returns the drain times of the goroutines started by Go which
+// returned after Context was cancelled, and of the shutdown hooks that have run,
+// longest first, so the components that chronically eat the drain budget can
+// be found. Goroutines registered in Wg directly are not tracked.
+//
+// Summaries of the drain times (see DrainStats) are logged when the final Wait
+// completes, and are included in Status, separately for tasks and hooks:
+// the time a task takes to notice the cancellation and the time a hook takes
+// to run are not comparable.
+func DrainTimes() []DrainTime { return std.DrainTimes() }
+
+// DrainTimes returns the drain times of tasks and hooks, longest first.
+// See the package-level DrainTimes.
+func (m *Manager) DrainTimes() []DrainTime {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sortedDrainTimes()
+}
+
+// sortedDrainTimes returns a copy of the drain times, longest first.
+// m.mu must be held.
+func (m *Manager) sortedDrainTimes() []DrainTime {
+	dts := append([]DrainTime(nil), m.drainTimes...)
+	sort.SliceStable(dts, func(i, j int) bool { return dts[i].Duration > dts[j].Duration })
+	return dts
+}
+
+// DrainStats is the distribution of the drain times of either tasks or hooks,
+// see DrainTimes. Field names follow proto conventions.
+type DrainStats struct {
+	// Count is the number of tracked tasks or hooks.
+	Count int `json:"count"`
+
+	// P50Seconds is the median drain time.
+	P50Seconds float64 `json:"p50_seconds"`
+
+	// P90Seconds is the 90th percentile of drain times.
+	P90Seconds float64 `json:"p90_seconds"`
+
+	// MaxSeconds is the longest drain time.
+	MaxSeconds float64 `json:"max_seconds"`
+
+	// Slowest holds the slowest (at most 3) tasks or hooks with their drain
+	// times, e.g. "example.com/app/worker.Start (worker.go:42) (1.5s)".
+	Slowest []string `json:"slowest,omitempty"`
+}
+
+// drainStats returns the distribution of the drain times of hooks (if hook is
+// true) or tasks, nil if there are none.
+func (m *Manager) drainStats(hook bool) *DrainStats {
+	return newDrainStats(m.DrainTimes(), hook)
+}
+
+// newDrainStats returns the distribution of the drain times of hooks (if hook
+// is true) or tasks in all (sorted longest first), nil if there are none.
+func newDrainStats(all []DrainTime, hook bool) *DrainStats {
+	var dts []DrainTime
+	for _, dt := range all {
+		if dt.Hook == hook {
+			dts = append(dts, dt)
+		}
+	}
+	if len(dts) == 0 {
+		return nil
+	}
+
+	// dts is sorted longest first.
+	percentile := func(p float64) float64 {
+		return dts[int(float64(len(dts)-1)*(1-p)+0.5)].Duration.Seconds()
+	}
+	st := &DrainStats{
+		Count:      len(dts),
+		P50Seconds: percentile(0.5),
+		P90Seconds: percentile(0.9),
+		MaxSeconds: dts[0].Duration.Seconds(),
+	}
+	for i := 0; i < len(dts) && i < 3; i++ {
+		st.Slowest = append(st.Slowest, fmt.Sprintf("%s (%v)", dts[i].Name, dts[i].Duration.Round(time.Millisecond)))
+	}
+	return st
+}
+
+// logDrainTimes logs the distributions of the drain times of tasks and hooks,
+// if any.
+func (m *Manager) logDrainTimes() {
+	sec := func(s float64) time.Duration {
+		return time.Duration(s * float64(time.Second)).Round(time.Millisecond)
+	}
+	for _, hook := range []bool{false, true} {
+		st := m.drainStats(hook)
+		if st == nil {
+			continue
+		}
+		kind := "tasks"
+		if hook {
+			kind = "hooks"
+		}
+		m.logf("Drain times of %d %s: p50 %v, p90 %v, max %v, slowest: %s.",
+			st.Count, kind, sec(st.P50Seconds), sec(st.P90Seconds), sec(st.MaxSeconds), strings.Join(st.Slowest, ", "))
+	}
+}
+
+// pkgPrefix is the prefix of the function names of the package.
+const pkgPrefix = "github.com/icza/shutdown."
+
+// callSite returns the package-qualified function and the location
+// (e.g. "example.com/app/worker.Start (worker.go:42)") of the first caller
+// outside the package (test files count as outside). The file name alone
+// would be ambiguous across packages.
+func callSite() string {
+	var pcs [16]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, pkgPrefix) || strings.HasSuffix(f.File, "_test.go") {
+			return f.Function + " (" + filepath.Base(f.File) + ":" + strconv.Itoa(f.Line) + ")"
+		}
+		if !more {
+			return "(unknown)"
+		}
+	}
+}
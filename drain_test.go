@@ -0,0 +1,53 @@
+package shutdown
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDrainTimes(t *testing.T) {
+	m := newTestManager()
+	m.Go(func() {}) // Returns before initiation, not tracked.
+	m.Go(func() {
+		<-m.C
+		time.Sleep(50 * time.Millisecond)
+	})
+	m.OnShutdown(func() { time.Sleep(20 * time.Millisecond) }, WithName("flush"))
+	time.Sleep(10 * time.Millisecond)
+	m.Close()
+
+	dts := m.DrainTimes()
+	if len(dts) != 2 {
+		t.Fatalf("got %d drain times (%v), want 2", len(dts), dts)
+	}
+	wantPrefix := "github.com/icza/shutdown.TestDrainTimes (drain_test.go:"
+	if !strings.HasPrefix(dts[0].Name, wantPrefix) || dts[0].Hook || dts[0].Duration < 50*time.Millisecond {
+		t.Errorf("slowest is %+v, want the task started in TestDrainTimes taking 50ms", dts[0])
+	}
+	if dts[1].Name != "flush" || !dts[1].Hook || dts[1].Duration < 20*time.Millisecond {
+		t.Errorf("second is %+v, want hook flush taking 20ms", dts[1])
+	}
+
+	// Tasks and hooks are kept in separate distributions.
+	st := m.Status()
+	if st.Drain == nil || st.Drain.Count != 1 || st.Drain.MaxSeconds != dts[0].Duration.Seconds() || len(st.Drain.Slowest) != 1 {
+		t.Errorf("Status Drain is %+v, want the stats of %v", st.Drain, dts[0])
+	}
+	if st.HookDrain == nil || st.HookDrain.Count != 1 || st.HookDrain.MaxSeconds != dts[1].Duration.Seconds() ||
+		len(st.HookDrain.Slowest) != 1 || !strings.HasPrefix(st.HookDrain.Slowest[0], "flush (") {
+		t.Errorf("Status HookDrain is %+v, want the stats of %v", st.HookDrain, dts[1])
+	}
+}
+
+func TestDrainTimesNone(t *testing.T) {
+	m := newTestManager()
+	m.Close()
+
+	if dts := m.DrainTimes(); len(dts) != 0 {
+		t.Errorf("got drain times %v, want none", dts)
+	}
+	if st := m.Status(); st.Drain != nil || st.HookDrain != nil {
+		t.Errorf("Status Drain is %+v, HookDrain is %+v, want nil", st.Drain, st.HookDrain)
+	}
+}
@@ -0,0 +1,105 @@
+package shutdown
+
+import "time"
+
+// Exit performs the final Wait, then exits the app with the exit code of
+// the shutdown:
+//   - the code set with SetExitCode if it was called,
+//   - 1 if InitiateError was called (see Err),
+//   - 128+signal number if shutdown was initiated by a signal,
+//   - 1 if any hook failed (see Errors),
+//   - 0 otherwise.
+//
+// Call it at the end of main() instead of Wait. Like Wait, it waits for
+// shutdown to be initiated first. Note that deferred functions of main() are
+// not run (os.Exit is called); use Defer instead.
+func Exit() { std.Exit() }
+
+// Exit performs the final Wait, then exits the app with the exit code of the
+// shutdown. See the package-level Exit.
+func (m *Manager) Exit() {
+	m.Wait()
+	m.exit(m.ExitCode(), "exit")
+}
+
+// SetExitCode sets the exit code used by Exit (and auto exit, see WithAutoExit),
+// overriding the default.
+func SetExitCode(code int) { std.SetExitCode(code) }
+
+// SetExitCode sets the exit code used by Exit. See the package-level SetExitCode.
+func (m *Manager) SetExitCode(code int) {
+	m.mu.Lock()
+	m.exitCode = &code
+	m.mu.Unlock()
+}
+
+// ExitCode returns the exit code Exit would use, see Exit.
+func ExitCode() int { return std.ExitCode() }
+
+// ExitCode returns the exit code Exit would use. See the package-level ExitCode.
+func (m *Manager) ExitCode() int {
+	m.mu.Lock()
+	exitCode, err, sig, failed := m.exitCode, m.err, m.signal, len(m.hookErrors) > 0
+	m.mu.Unlock()
+
+	switch {
+	case exitCode != nil:
+		return *exitCode
+	case err != nil:
+		return 1
+	case sig != nil:
+		return signalExitCode(sig)
+	case failed:
+		return 1
+	}
+	return 0
+}
+
+// lastGaspTimeout is the max time the last-gasp function is waited for.
+const lastGaspTimeout = time.Second
+
+// SetLastGasp sets a function to be called right before the app is exited by
+// this package: on forced exits (escalation, the watchdog, the hard kill
+// timer), on fast exit (see WithFastExit) and by Exit. reason is the reason of
+// the exit: "escalation", "watchdog", "hard kill", "fast exit" or "exit".
+//
+// fn should be short (e.g. write a crash marker file): it is called only once,
+// and it is waited for no longer than 1 second. A panic in fn is logged.
+func SetLastGasp(fn func(reason string)) { std.SetLastGasp(fn) }
+
+// SetLastGasp sets a function to be called right before the app is exited.
+// See the package-level SetLastGasp.
+func (m *Manager) SetLastGasp(fn func(reason string)) {
+	m.lastGaspMu.Lock()
+	m.lastGasp = fn
+	m.lastGaspMu.Unlock()
+}
+
+// callLastGasp calls the last-gasp function once (no longer than lastGaspTimeout).
+// It doesn't take m.mu, see hardKillExit.
+func (m *Manager) callLastGasp(reason string) {
+	m.lastGaspOnce.Do(func() {
+		m.lastGaspMu.Lock()
+		fn := m.lastGasp
+		m.lastGaspMu.Unlock()
+
+		if fn == nil {
+			return
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			m.callRecover("last-gasp function", func() { fn(reason) })
+		}()
+
+		t := time.NewTimer(lastGaspTimeout)
+		defer t.Stop()
+
+		select {
+		case <-done:
+		case <-t.C:
+			m.warnf("Last-gasp function did not complete within %v.", lastGaspTimeout)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package shutdown
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLastGasp(t *testing.T) {
+	m := newTestManager()
+	var reasons []string
+	m.SetLastGasp(func(reason string) {
+		reasons = append(reasons, reason)
+		panic("boom")
+	})
+
+	m.callLastGasp("watchdog")
+	m.callLastGasp("escalation")
+
+	if len(reasons) != 1 || reasons[0] != "watchdog" {
+		t.Errorf("last gasp called with %q, want [watchdog]", reasons)
+	}
+}
+
+// TestHardKillStuckMutex checks that the hard kill exits the app even if the
+// mutex of the Manager is held by a stuck goroutine. The app is the test
+// binary itself, run in a child process.
+func TestHardKillStuckMutex(t *testing.T) {
+	if os.Getenv("SHUTDOWN_TEST_HARD_KILL") == "1" {
+		m := newTestManager(WithHardKill(50 * time.Millisecond))
+		m.InitiateManual()
+		m.mu.Lock() // Simulate a deadlock inside the package.
+		time.Sleep(10 * time.Second)
+		os.Exit(0)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHardKillStuckMutex$")
+	cmd.Env = append(os.Environ(), "SHUTDOWN_TEST_HARD_KILL=1")
+	start := time.Now()
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+		t.Fatalf("child exited with %v, want exit code 2", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("hard kill took %v", d)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		override int // 0 means no SetExitCode
+		want     int
+	}{
+		{"signal", 0, 143},
+		{"override", 7, 7},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if inChild(t) {
+				m := New(WithSignals(syscall.SIGTERM))
+				if c.override != 0 {
+					m.SetExitCode(c.override)
+				}
+				raise(syscall.SIGTERM)
+				m.Exit()
+			}
+
+			code, out := runChild(t)
+			if code != c.want {
+				t.Errorf("child exited with %d, want %d, output:\n%s", code, c.want, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !plan9
+
+package shutdown
+
+import (
+	"os"
+	"syscall"
+)
+
+// signalExitCode returns the conventional exit code of the app terminated
+// by sig: 128+signal number, or 1 if sig is not a syscall.Signal.
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
@@ -0,0 +1,11 @@
+//go:build plan9
+
+package shutdown
+
+import "os"
+
+// signalExitCode returns the exit code of the app terminated by sig.
+// Plan 9 notes have no numbers, so it is always 1.
+func signalExitCode(sig os.Signal) int {
+	return 1
+}
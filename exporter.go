@@ -0,0 +1,51 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Exporter is a telemetry exporter or provider flushing buffered data,
+// e.g. an OpenTelemetry *trace.TracerProvider or *metric.MeterProvider.
+type Exporter interface {
+	ForceFlush(ctx context.Context) error
+	Shutdown(ctx context.Context) error
+}
+
+// TrackExporter registers e to be flushed and shut down in PhaseCleanup, so the
+// spans and metrics recorded during the earlier phases of the shutdown are not
+// lost. name is used in logs and errors.
+//
+// ForceFlush and Shutdown are each given timeout (if positive) through their ctx.
+// Their errors are recorded as the error of the hook (see Errors).
+func TrackExporter(name string, e Exporter, timeout time.Duration) {
+	std.TrackExporter(name, e, timeout)
+}
+
+// TrackExporter registers e to be flushed and shut down in PhaseCleanup.
+// See the package-level TrackExporter.
+func (m *Manager) TrackExporter(name string, e Exporter, timeout time.Duration) {
+	m.OnPhaseError(PhaseCleanup, func() error {
+		var errs []error
+		if err := callTimeout(timeout, e.ForceFlush); err != nil {
+			errs = append(errs, fmt.Errorf("flush %s: %w", name, err))
+		}
+		if err := callTimeout(timeout, e.Shutdown); err != nil {
+			errs = append(errs, fmt.Errorf("shut down %s: %w", name, err))
+		}
+		return errors.Join(errs...)
+	}, WithName(name))
+}
+
+// callTimeout calls f with a context having timeout (if positive).
+func callTimeout(timeout time.Duration, f func(ctx context.Context) error) error {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return f(ctx)
+}
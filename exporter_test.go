@@ -0,0 +1,42 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeExporter is an Exporter recording its calls.
+type fakeExporter struct {
+	r           *recorder
+	shutdownErr error
+}
+
+func (e *fakeExporter) ForceFlush(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		return errors.New("no deadline")
+	}
+	e.r.add("flush")
+	return nil
+}
+
+func (e *fakeExporter) Shutdown(ctx context.Context) error {
+	e.r.add("shutdown")
+	return e.shutdownErr
+}
+
+func TestTrackExporter(t *testing.T) {
+	m := newTestManager()
+	r := &recorder{}
+	errShutdown := errors.New("exporter failed")
+	m.OnShutdown(r.addFunc("stop-hook"))
+	m.TrackExporter("tracer", &fakeExporter{r: r, shutdownErr: errShutdown}, time.Second)
+
+	if err := m.Close(); !errors.Is(err, errShutdown) {
+		t.Errorf("Close returned %v, want %v", err, errShutdown)
+	}
+	if got, want := r.String(), "stop-hook,flush,shutdown"; got != want {
+		t.Errorf("events are %q, want %q", got, want)
+	}
+}
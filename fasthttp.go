@@ -0,0 +1,48 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// FastHTTPServer is the subset of the methods of fasthttp.Server used by
+// FastHTTP. It is declared here so this package doesn't depend on fasthttp.
+type FastHTTPServer interface {
+	Serve(ln net.Listener) error
+	ShutdownWithContext(ctx context.Context) error
+	GetOpenConnectionsCount() int32
+}
+
+// FastHTTP adapts a fasthttp.Server serving on ln to a GracefulServer,
+// so it can be used with Manage:
+//
+//	ln, err := net.Listen("tcp", ":8080")
+//	// handle err
+//	shutdown.Manage("fasthttp server", shutdown.FastHTTP(srv, ln))
+//
+// On shutdown the server stops accepting connections, and open connections
+// are waited for until the shutdown context expires (the server shutdown
+// timeout when used with Manage, see WithServerShutdownTimeout).
+func FastHTTP(srv FastHTTPServer, ln net.Listener) GracefulServer {
+	return fastHTTPServer{srv: srv, ln: ln}
+}
+
+// fastHTTPServer implements GracefulServer for a FastHTTPServer.
+type fastHTTPServer struct {
+	srv FastHTTPServer
+	ln  net.Listener
+}
+
+// Serve implements GracefulServer.
+func (s fastHTTPServer) Serve() error {
+	return s.srv.Serve(s.ln)
+}
+
+// Shutdown implements GracefulServer.
+func (s fastHTTPServer) Shutdown(ctx context.Context) error {
+	if err := s.srv.ShutdownWithContext(ctx); err != nil {
+		return fmt.Errorf("%w (%d open connections)", err, s.srv.GetOpenConnectionsCount())
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeFastHTTPServer is a FastHTTPServer with open connections finishing when
+// connsDone is closed.
+type fakeFastHTTPServer struct {
+	stopped   chan struct{}
+	conns     atomic.Int32
+	connsDone chan struct{}
+}
+
+func newFakeFastHTTPServer(conns int32) *fakeFastHTTPServer {
+	s := &fakeFastHTTPServer{stopped: make(chan struct{}), connsDone: make(chan struct{})}
+	s.conns.Store(conns)
+	return s
+}
+
+func (s *fakeFastHTTPServer) Serve(ln net.Listener) error {
+	<-s.stopped
+	return nil
+}
+
+func (s *fakeFastHTTPServer) ShutdownWithContext(ctx context.Context) error {
+	close(s.stopped) // Stop accepting first, then drain open connections.
+	select {
+	case <-s.connsDone:
+		s.conns.Store(0)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *fakeFastHTTPServer) GetOpenConnectionsCount() int32 { return s.conns.Load() }
+
+func TestFastHTTP(t *testing.T) {
+	m := newTestManager()
+	srv := newFakeFastHTTPServer(1)
+	m.Manage("fasthttp", FastHTTP(srv, nil))
+	time.AfterFunc(20*time.Millisecond, func() { close(srv.connsDone) })
+
+	m.InitiateManual()
+	m.Wait() // Waits for the open connection to finish.
+
+	if n := srv.GetOpenConnectionsCount(); n != 0 {
+		t.Errorf("%d open connections after Wait, want 0", n)
+	}
+}
+
+func TestFastHTTPTimeout(t *testing.T) {
+	srv := newFakeFastHTTPServer(2)
+	gs := FastHTTP(srv, nil)
+	go gs.Serve()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := gs.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "(2 open connections)") {
+		t.Errorf("Shutdown returned %v, want deadline exceeded with 2 open connections", err)
+	}
+}
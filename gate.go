@@ -0,0 +1,73 @@
+package shutdown
+
+// Gate admits units of work until shutdown is initiated, and refuses new work
+// afterwards. Admitted work is registered in Wg, so the final Wait waits for
+// work in flight.
+//
+// It replaces the following pattern:
+//
+//	if shutdown.Initiated() {
+//		return ErrShuttingDown
+//	}
+//	shutdown.Wg.Add(1)
+//	defer shutdown.Wg.Done()
+//
+// The zero Gate is ready to use and is bound to the default Manager.
+// Use Manager.NewGate to create a Gate bound to another Manager.
+type Gate struct {
+	m *Manager
+}
+
+// NewGate returns a new Gate bound to m.
+func (m *Manager) NewGate() *Gate {
+	return &Gate{m: m}
+}
+
+// manager returns the manager the gate is bound to.
+func (g *Gate) manager() *Manager {
+	if g.m == nil {
+		return std
+	}
+	return g.m
+}
+
+// Acquire admits a unit of work. It returns false if shutdown has been
+// initiated (or the final Wait has returned), in which case the work must
+// not be started. If it returns true,
+// Release must be called when the work completes.
+func (g *Gate) Acquire() bool {
+	m := g.manager()
+
+	// Like Go, check and register under m.mu, so no work is added to Wg
+	// once the final Wait may see a zero counter.
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Initiated() || m.addRefused() {
+		return false
+	}
+	m.Wg.Add(1)
+	m.tasks++
+	return true
+}
+
+// Release marks the end of a unit of work admitted by Acquire.
+func (g *Gate) Release() {
+	m := g.manager()
+
+	m.mu.Lock()
+	m.tasks--
+	m.mu.Unlock()
+	m.Wg.Done()
+}
+
+// Do calls f if the gate admits it, and returns its error. If shutdown has been
+// initiated, f is not called and ErrInitiated is returned.
+func (g *Gate) Do(f func() error) error {
+	if !g.Acquire() {
+		return ErrInitiated
+	}
+	defer g.Release()
+
+	return f()
+}
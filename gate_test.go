@@ -0,0 +1,141 @@
+package shutdown
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestGate(t *testing.T) {
+	m := newTestManager()
+	g := m.NewGate()
+
+	called := false
+	if err := g.Do(func() error { called = true; return nil }); err != nil || !called {
+		t.Fatalf("Do before shutdown: err = %v, called = %v", err, called)
+	}
+
+	if !g.Acquire() {
+		t.Fatal("Acquire before shutdown refused")
+	}
+	m.InitiateManual()
+	<-m.C
+
+	if g.Acquire() {
+		t.Error("Acquire after shutdown admitted")
+	}
+	called = false
+	if err := g.Do(func() error { called = true; return nil }); !errors.Is(err, ErrInitiated) || called {
+		t.Errorf("Do after shutdown: err = %v, called = %v", err, called)
+	}
+
+	waitDone := make(chan struct{})
+	go func() {
+		m.Wait()
+		close(waitDone)
+	}()
+	select {
+	case <-waitDone:
+		t.Fatal("Wait returned while admitted work is in flight")
+	default:
+	}
+	g.Release()
+	<-waitDone
+}
+
+func TestGateAcquireDuringWait(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		m := newTestManager()
+		g := m.NewGate()
+
+		stop := make(chan struct{})
+		wg := &sync.WaitGroup{}
+		for j := 0; j < 8; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					if g.Acquire() {
+						g.Release()
+					}
+					runtime.Gosched()
+				}
+			}()
+		}
+
+		m.InitiateManual()
+		m.Wait()
+		close(stop)
+		wg.Wait()
+
+		if g.Acquire() {
+			t.Fatal("Acquire after Wait admitted")
+		}
+	}
+}
+
+func TestGateWaitBeforeInitiation(t *testing.T) {
+	m := newTestManager()
+	g := m.NewGate()
+	m.Go(func() { <-m.C })
+
+	waitDone := make(chan struct{})
+	go func() {
+		m.Wait()
+		close(waitDone)
+	}()
+	waitUntilWaiting(m)
+
+	if !g.Acquire() {
+		t.Error("Acquire refused before initiation while the final Wait is waiting")
+	} else {
+		time.AfterFunc(10*time.Millisecond, g.Release)
+	}
+
+	m.InitiateManual()
+	<-waitDone
+	if g.Acquire() {
+		t.Error("Acquire admitted after the final Wait")
+	}
+}
+
+// TestGateFastExit checks that work admitted by a Gate keeps fast exit from
+// exiting the app (see WithFastExit).
+func TestGateFastExit(t *testing.T) {
+	if inChild(t) {
+		m := New(WithFastExit(), WithSignals(syscall.SIGTERM))
+		g := m.NewGate()
+		done := false
+		go g.Do(func() error {
+			time.Sleep(300 * time.Millisecond)
+			done = true
+			return nil
+		})
+		time.Sleep(50 * time.Millisecond)
+		raise(syscall.SIGTERM)
+		<-m.C
+		m.Wait()
+		if !done {
+			os.Exit(3)
+		}
+		os.Exit(0)
+	}
+
+	code, out := runChild(t)
+	if code != 0 {
+		t.Errorf("child exited with %d, want 0, output:\n%s", code, out)
+	}
+	if strings.Contains(out, "nothing to shut down") {
+		t.Errorf("fast exit with work in flight, output:\n%s", out)
+	}
+}
@@ -0,0 +1,85 @@
+package shutdown
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// WithGRPCClientDrainTimeout sets the max time to wait for in-flight critical
+// RPCs of connections registered with TrackGRPCClient. The default is 20 seconds.
+func WithGRPCClientDrainTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.grpcClientDrainTimeout = timeout
+	}
+}
+
+// GRPCClient tracks in-flight critical RPCs made on a gRPC client connection.
+// Create one using TrackGRPCClient.
+type GRPCClient struct {
+	mu sync.Mutex
+	n  int // Number of in-flight critical RPCs
+}
+
+// TrackGRPCClient registers conn (e.g. a *grpc.ClientConn) to be closed in
+// PhaseCleanup, so final outbound calls made by hooks of earlier phases don't
+// fail with "transport is closing" errors. name is used in logs.
+//
+// Before closing conn, in-flight RPCs marked critical (see GRPCClient.Critical)
+// are waited for, no longer than the drain timeout (see
+// WithGRPCClientDrainTimeout).
+func TrackGRPCClient(name string, conn io.Closer) *GRPCClient {
+	return std.TrackGRPCClient(name, conn)
+}
+
+// TrackGRPCClient registers conn to be closed in PhaseCleanup.
+// See the package-level TrackGRPCClient.
+func (m *Manager) TrackGRPCClient(name string, conn io.Closer) *GRPCClient {
+	c := &GRPCClient{}
+
+	m.OnPhase(PhaseCleanup, func() {
+		if n := c.count(); n > 0 {
+			m.logf("Waiting for %d in-flight critical RPC(s) of %s...", n, name)
+			ctx, cancel := context.WithTimeout(context.Background(), m.grpcClientDrainTimeout)
+			err := waitFor(ctx, nil, 10*time.Millisecond, func() bool { return c.count() == 0 })
+			cancel()
+			if err != nil {
+				m.warnf("Timed out waiting for %d in-flight critical RPC(s) of %s.", c.count(), name)
+			}
+		}
+
+		if err := conn.Close(); err != nil {
+			m.errorf("Failed to close %s: %v", name, err)
+		}
+	}, WithName(name))
+
+	return c
+}
+
+// Critical marks the start of a critical RPC, which is waited for before
+// the connection is closed. The returned function must be called when the RPC
+// completes; calling it more than once has no additional effect.
+//
+//	done := c.Critical()
+//	defer done()
+//	_, err := client.Flush(ctx, req)
+func (c *GRPCClient) Critical() (done func()) {
+	c.add(1)
+	var once sync.Once
+	return func() { once.Do(func() { c.add(-1) }) }
+}
+
+// add adds delta to the in-flight counter.
+func (c *GRPCClient) add(delta int) {
+	c.mu.Lock()
+	c.n += delta
+	c.mu.Unlock()
+}
+
+// count returns the number of in-flight critical RPCs.
+func (c *GRPCClient) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
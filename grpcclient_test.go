@@ -0,0 +1,70 @@
+package shutdown
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeConn is a connection recording when it is closed.
+type fakeConn struct {
+	closed int32
+}
+
+func (c *fakeConn) Close() error {
+	atomic.StoreInt32(&c.closed, 1)
+	return nil
+}
+
+func (c *fakeConn) isClosed() bool {
+	return atomic.LoadInt32(&c.closed) == 1
+}
+
+func TestTrackGRPCClient(t *testing.T) {
+	m := newTestManager()
+	conn := &fakeConn{}
+	c := m.TrackGRPCClient("grpc conn", conn)
+
+	// Hooks of earlier phases may still use the connection.
+	var closedInStop bool
+	m.OnPhase(PhaseStop, func() { closedInStop = conn.isClosed() })
+
+	// In-flight critical RPC when shutdown is initiated.
+	done := c.Critical()
+	var rpcDone int32
+	time.AfterFunc(50*time.Millisecond, func() {
+		atomic.StoreInt32(&rpcDone, 1)
+		done()
+		done() // No additional effect.
+	})
+
+	m.Close()
+	if closedInStop {
+		t.Error("connection closed before PhaseCleanup")
+	}
+	if atomic.LoadInt32(&rpcDone) != 1 {
+		t.Error("connection closed before the critical RPC completed")
+	}
+	if !conn.isClosed() {
+		t.Error("connection not closed")
+	}
+	if n := c.count(); n != 0 {
+		t.Errorf("%d critical RPCs in flight, want 0", n)
+	}
+}
+
+func TestTrackGRPCClientTimeout(t *testing.T) {
+	m := newTestManager(WithGRPCClientDrainTimeout(50 * time.Millisecond))
+	conn := &fakeConn{}
+	c := m.TrackGRPCClient("grpc conn", conn)
+	c.Critical() // Never completes.
+
+	start := time.Now()
+	m.Close()
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("drain took %v, want about the drain timeout", d)
+	}
+	if !conn.isClosed() {
+		t.Error("connection not closed after the drain timeout")
+	}
+}
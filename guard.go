@@ -0,0 +1,79 @@
+package shutdown
+
+import "time"
+
+// Enter marks the start of a critical section: a small atomic unit of work that
+// must finish even though the goroutine doing it may exit on shutdown (e.g. a
+// request handler writing a record). Leave must be called when it completes.
+//
+// Unlike holds (see Hold), critical sections do not delay the broadcast of the
+// shutdown. Unlike Wg, they are waited for by the final Wait after Wg and the
+// hooks, right before the servers registered with ManageLast are shut down.
+// While waiting, the number of open critical sections is logged periodically.
+// They are waited for no longer than the grace timeout (see WithGraceTimeout),
+// and not at all once shutdown has been escalated (see EscalateCritical).
+func Enter() { std.Enter() }
+
+// Enter marks the start of a critical section. See the package-level Enter.
+func (m *Manager) Enter() {
+	m.mu.Lock()
+	m.guards++
+	m.mu.Unlock()
+}
+
+// Leave marks the end of a critical section started with Enter.
+// It panics if there is no open critical section.
+func Leave() { std.Leave() }
+
+// Leave marks the end of a critical section. See the package-level Leave.
+func (m *Manager) Leave() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.guards == 0 {
+		panic("shutdown: Leave without Enter")
+	}
+	m.guards--
+}
+
+// waitGuards waits for the open critical sections to complete, logging their
+// number every second. Like waiting for Wg, it respects the grace timeout and
+// escalation, and it doesn't wait once an exit has begun.
+func (m *Manager) waitGuards() {
+	var graceC <-chan struct{}
+	if m.graceTimeout > 0 {
+		graceC = m.deadline(m.graceTimeout)
+	}
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	lastLog := time.Now()
+	for first := true; ; first = false {
+		m.mu.Lock()
+		n, exiting := m.guards, m.exiting
+		m.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		if exiting {
+			m.warnf("Exit has begun, not waiting for %d open critical section(s).", n)
+			return
+		}
+		if first || time.Since(lastLog) >= time.Second {
+			m.logf("Waiting for %d open critical section(s)...", n)
+			lastLog = time.Now()
+		}
+
+		select {
+		case <-ticker.C:
+		case <-graceC:
+			m.warnf("Shutdown grace timeout (%v) exceeded, not waiting for %d open critical section(s).", m.graceTimeout, n)
+			m.beginExit()
+			return
+		case <-m.hurry:
+			m.warnf("Shutdown escalated, not waiting for %d open critical section(s).", n)
+			return
+		}
+	}
+}
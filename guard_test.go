@@ -0,0 +1,73 @@
+package shutdown
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWaitGuards(t *testing.T) {
+	m := newTestManager()
+	m.Enter()
+	m.InitiateManual()
+	<-m.C
+
+	waitDone := make(chan struct{})
+	go func() {
+		m.Wait()
+		close(waitDone)
+	}()
+
+	select {
+	case <-waitDone:
+		t.Fatal("Wait returned with an open critical section")
+	case <-time.After(50 * time.Millisecond):
+	}
+	m.Leave()
+	select {
+	case <-waitDone:
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after Leave")
+	}
+}
+
+func TestWaitGuardsGraceTimeout(t *testing.T) {
+	m := newTestManager(WithGraceTimeout(100 * time.Millisecond))
+	m.Enter()
+	defer m.Leave()
+	m.InitiateManual()
+	<-m.C
+
+	start := time.Now()
+	m.Wait()
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Wait took %v, exceeding the grace timeout", d)
+	}
+}
+
+func TestWaitGuardsEscalated(t *testing.T) {
+	m := newTestManager()
+	m.Enter()
+	defer m.Leave()
+	m.InitiateManual()
+	<-m.C
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		m.escalateCritical()
+	}()
+
+	start := time.Now()
+	m.Wait()
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Wait took %v after escalation", d)
+	}
+}
+
+func TestLeaveWithoutEnter(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Leave without Enter did not panic")
+		}
+	}()
+	newTestManager().Leave()
+}
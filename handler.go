@@ -0,0 +1,65 @@
+package shutdown
+
+import (
+	"context"
+	"time"
+)
+
+// DrainHandler wraps the message handler h (of any messaging protocol) to take
+// part in the shutdown:
+//
+//   - in-flight handlers are waited for by the final Wait;
+//   - after shutdown has been initiated, new messages are refused: nack is
+//     called, and ErrInitiated is returned without calling h;
+//   - handlers still running when budget has passed since shutdown initiation
+//     have their ctx cancelled, and if they then return an error, nack is
+//     called so the message can be requeued.
+//
+// nack may be nil if there's nothing to do with refused or aborted messages.
+func DrainHandler[M any](h func(ctx context.Context, msg M) error, nack func(msg M), budget time.Duration) func(ctx context.Context, msg M) error {
+	return DrainHandlerOn(std, h, nack, budget)
+}
+
+// DrainHandlerOn is like DrainHandler, but the handler takes part in
+// the shutdown managed by m.
+func DrainHandlerOn[M any](m *Manager, h func(ctx context.Context, msg M) error, nack func(msg M), budget time.Duration) func(ctx context.Context, msg M) error {
+	if nack == nil {
+		nack = func(M) {}
+	}
+
+	// budgetCtx is cancelled when budget has passed since shutdown initiation.
+	budgetCtx, budgetCancel := context.WithCancel(context.Background())
+	go func() {
+		<-m.C
+		time.Sleep(budget)
+		budgetCancel()
+	}()
+
+	gate := m.NewGate()
+	return func(ctx context.Context, msg M) error {
+		if !gate.Acquire() {
+			nack(msg)
+			return ErrInitiated
+		}
+		defer gate.Release()
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-budgetCtx.Done():
+				cancel()
+			case <-done:
+			}
+		}()
+
+		err := h(ctx, msg)
+		if err != nil && budgetCtx.Err() != nil {
+			nack(msg)
+		}
+		return err
+	}
+}
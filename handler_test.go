@@ -0,0 +1,105 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// nacks records nacked messages.
+type nacks struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (n *nacks) nack(msg string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.msgs = append(n.msgs, msg)
+}
+
+func (n *nacks) get() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string(nil), n.msgs...)
+}
+
+func TestDrainHandlerInFlight(t *testing.T) {
+	m := newTestManager()
+	ns := &nacks{}
+	started := make(chan struct{})
+	h := DrainHandlerOn(m, func(ctx context.Context, msg string) error {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		return ctx.Err()
+	}, ns.nack, time.Second)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- h(context.Background(), "in-flight") }()
+	<-started
+	m.InitiateManual()
+	m.Wait() // Waits for the handler in flight.
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("handler finishing within budget returned %v, want nil", err)
+		}
+	default:
+		t.Error("Wait returned before the handler in flight")
+	}
+	if got := ns.get(); len(got) != 0 {
+		t.Errorf("nacked %v, want none", got)
+	}
+}
+
+func TestDrainHandlerBudget(t *testing.T) {
+	m := newTestManager()
+	ns := &nacks{}
+	started := make(chan struct{})
+	var cancelledAfter time.Duration
+	h := DrainHandlerOn(m, func(ctx context.Context, msg string) error {
+		close(started)
+		<-ctx.Done()
+		cancelledAfter = m.Duration()
+		return ctx.Err()
+	}, ns.nack, 30*time.Millisecond)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- h(context.Background(), "slow") }()
+	<-started
+	time.Sleep(50 * time.Millisecond) // Time before initiation doesn't count.
+	m.InitiateManual()
+	m.Wait()
+
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Errorf("handler returned %v, want %v", err, context.Canceled)
+	}
+	if cancelledAfter < 30*time.Millisecond {
+		t.Errorf("handler cancelled %v after initiation, want at least the budget", cancelledAfter)
+	}
+	if got := ns.get(); len(got) != 1 || got[0] != "slow" {
+		t.Errorf("nacked %v, want [slow]", got)
+	}
+}
+
+func TestDrainHandlerRefused(t *testing.T) {
+	m := newTestManager()
+	ns := &nacks{}
+	h := DrainHandlerOn(m, func(ctx context.Context, msg string) error {
+		t.Errorf("handler called with %q after initiation", msg)
+		return nil
+	}, ns.nack, time.Second)
+
+	m.InitiateManual()
+	<-m.C
+	if err := h(context.Background(), "new"); !errors.Is(err, ErrInitiated) {
+		t.Errorf("handler returned %v, want %v", err, ErrInitiated)
+	}
+	if got := ns.get(); len(got) != 1 || got[0] != "new" {
+		t.Errorf("nacked %v, want [new]", got)
+	}
+	m.Wait()
+}
@@ -0,0 +1,83 @@
+package shutdown
+
+import (
+	"sync"
+	"time"
+)
+
+// WithHoldTimeout sets the max time the broadcast of the shutdown is delayed by
+// holds (see Hold). The default is 5 seconds.
+func WithHoldTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.holdTimeout = timeout
+	}
+}
+
+// Hold delays the broadcast of the shutdown until the returned release function
+// is called, so short critical sections (e.g. committing a payment) can finish
+// before the shutdown is broadcast (before C is closed). The broadcast waits for
+// all holds no longer than the hold timeout (see WithHoldTimeout). Calling
+// release more than once has no additional effect.
+//
+// Once shutdown has been initiated, new holds do not delay the broadcast
+// (Hold returns a release function doing nothing).
+func Hold() (release func()) { return std.Hold() }
+
+// Hold delays the broadcast of the shutdown until release is called.
+// See the package-level Hold.
+func (m *Manager) Hold() (release func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.initiatedAt.IsZero() {
+		return func() {}
+	}
+	m.holds++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			m.holds--
+			zero := m.holds == 0
+			m.mu.Unlock()
+
+			if zero {
+				select {
+				case m.holdsReleased <- struct{}{}:
+				default:
+				}
+			}
+		})
+	}
+}
+
+// waitHolds waits for all holds to be released, no longer than m.holdTimeout.
+func (m *Manager) waitHolds() {
+	m.mu.Lock()
+	n := m.holds
+	m.mu.Unlock()
+	if n == 0 {
+		return
+	}
+
+	m.logf("Waiting for %d hold(s) before broadcasting shutdown...", n)
+	t := time.NewTimer(m.holdTimeout)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-m.holdsReleased:
+		case <-t.C:
+			m.warnf("Hold timeout (%v) exceeded, broadcasting shutdown.", m.holdTimeout)
+			return
+		}
+
+		m.mu.Lock()
+		n = m.holds
+		m.mu.Unlock()
+		if n == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package shutdown
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHold(t *testing.T) {
+	m := newTestManager()
+	release1, release2 := m.Hold(), m.Hold()
+
+	m.InitiateManual()
+	time.Sleep(20 * time.Millisecond)
+	if m.Initiated() {
+		t.Fatal("shutdown broadcast while held")
+	}
+
+	// New holds after initiation don't delay the broadcast.
+	m.Hold()
+
+	release1()
+	release1() // No additional effect.
+	time.Sleep(20 * time.Millisecond)
+	if m.Initiated() {
+		t.Fatal("shutdown broadcast while held")
+	}
+
+	release2()
+	select {
+	case <-m.C:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown not broadcast after all holds were released")
+	}
+	m.Wait()
+}
+
+func TestHoldTimeout(t *testing.T) {
+	m := newTestManager(WithHoldTimeout(30 * time.Millisecond))
+	m.Hold() // Never released.
+
+	start := time.Now()
+	m.InitiateManual()
+	<-m.C
+	if d := time.Since(start); d < 30*time.Millisecond || d > 5*time.Second {
+		t.Errorf("shutdown broadcast after %v, want about the hold timeout", d)
+	}
+	m.Wait()
+}
@@ -0,0 +1,809 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"runtime/trace"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Phase is a named shutdown phase. Phases are executed sequentially in this
+// order: PhaseDrain, PhaseStop, PhaseCleanup, PhaseCgo.
+type Phase string
+
+// Shutdown phases.
+const (
+	// PhaseDrain is for stopping intake and draining in-flight work.
+	PhaseDrain Phase = "drain"
+
+	// PhaseStop is for stopping components.
+	// Hooks registered with OnShutdown, OnShutdownPriority and Defer are run in this phase.
+	PhaseStop Phase = "stop"
+
+	// PhaseCleanup is for final cleanup (e.g. flushing logs).
+	PhaseCleanup Phase = "cleanup"
+
+	// PhaseCgo is for releasing resources of C libraries, see OnCgoCleanup.
+	PhaseCgo Phase = "cgo"
+)
+
+// phases holds the phases in execution order.
+var phases = []Phase{PhaseDrain, PhaseStop, PhaseCleanup, PhaseCgo}
+
+// WithBestEffortTimeout sets the max time best-effort hooks are waited for
+// (see WithBestEffort). The default is 100 milliseconds.
+func WithBestEffortTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.bestEffortTimeout = timeout
+	}
+}
+
+// Errors of hooks not completed due to escalation.
+var (
+	errSkipped    = errors.New("skipped (shutdown escalated)")
+	errAbandoned  = errors.New("abandoned (shutdown escalated)")
+	errNoMainLoop = errors.New("not run (MainLoop has returned)")
+)
+
+// HookError is the error of a shutdown hook, see Errors and HooksErr.
+type HookError struct {
+	// Hook is the name of the hook, see WithName.
+	Hook string
+
+	// Phase is the phase the hook was run in.
+	Phase Phase
+
+	// Duration is the time the hook was waited for.
+	Duration time.Duration
+
+	// TimedOut tells if the hook timed out.
+	TimedOut bool
+
+	// Err is the error of the hook (the error it returned, its panic,
+	// or the cause of not waiting for it).
+	Err error
+
+	// Stack is the stack trace of the panic of the hook, empty if the hook
+	// did not panic.
+	Stack string
+}
+
+// Error returns the error message, e.g. "shutdown hook db: timed out after 1s".
+func (e *HookError) Error() string {
+	return fmt.Sprintf("shutdown hook %v: %v", e.Hook, e.Err)
+}
+
+// Unwrap returns the error of the hook.
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// HookErrors is a multi-error holding the errors of shutdown hooks, see HooksErr.
+// Use errors.As to extract the errors of specific hooks.
+type HookErrors []*HookError
+
+// Error returns the errors of the hooks, one per line, each followed by the
+// stack trace of its panic (if any).
+func (es HookErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+		if e.Stack != "" {
+			msgs[i] += "\n" + strings.TrimSuffix(e.Stack, "\n")
+		}
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors of the hooks, so errors.Is and errors.As
+// inspect all of them.
+func (es HookErrors) Unwrap() []error {
+	errs := make([]error, len(es))
+	for i, e := range es {
+		errs[i] = e
+	}
+	return errs
+}
+
+// hook is a registered shutdown hook.
+type hook struct {
+	name       string
+	prio       int
+	seq        int
+	timeout    time.Duration
+	class      string
+	bestEffort bool
+	critical   bool
+	mainThread bool
+	osThread   bool
+	f          func() error
+
+	// idx is the index of the hook in Manager.hookStatuses, set when run.
+	idx int
+}
+
+// HookOption is an option of a shutdown hook.
+type HookOption func(h *hook)
+
+// WithName sets the name of the hook, used in logs.
+func WithName(name string) HookOption {
+	return func(h *hook) {
+		h.name = name
+	}
+}
+
+// WithSequence sets the sequence number of the hook, a tie-break among hooks
+// of equal priority (see OnShutdownPriority): they are run in ascending order
+// of their sequence numbers, and in registration order only if those are equal
+// too. The default is 0.
+//
+// Registration order is only reproducible if hooks are registered from a
+// single goroutine; hooks registered concurrently (e.g. from the init code of
+// components started in parallel) can be given explicit sequence numbers to
+// make the shutdown behave the same across runs.
+func WithSequence(seq int) HookOption {
+	return func(h *hook) {
+		h.seq = seq
+	}
+}
+
+// WithTimeout sets a timeout for the hook. If the hook does not complete
+// within the timeout, a warning is logged, and shutdown moves on (the hook
+// is not waited for anymore), so a stuck hook can't hang the whole shutdown.
+func WithTimeout(timeout time.Duration) HookOption {
+	return func(h *hook) {
+		h.timeout = timeout
+	}
+}
+
+// WithClass sets the resource class of the hook (e.g. "network", "disk", "cpu").
+// The number of concurrently running hooks of a class can be limited using
+// SetClassLimit, so e.g. disk-heavy flush hooks don't all run at once and
+// starve each other.
+func WithClass(class string) HookOption {
+	return func(h *hook) {
+		h.class = class
+	}
+}
+
+// WithBestEffort marks the hook as best effort, for nice-to-have cleanups
+// (e.g. analytics pings): it is waited for no longer than the best-effort
+// timeout (see WithBestEffortTimeout), or its timeout if shorter (see
+// WithTimeout), and its failures are logged, but not recorded (they are not
+// returned by Errors).
+func WithBestEffort() HookOption {
+	return func(h *hook) {
+		h.bestEffort = true
+	}
+}
+
+// WithCritical marks the hook as critical: it is run even if shutdown is
+// escalated (see WithEscalation and EscalateCritical), when non-critical
+// hooks are skipped or abandoned.
+func WithCritical() HookOption {
+	return func(h *hook) {
+		h.critical = true
+	}
+}
+
+// WithMainThread makes the hook run on the main goroutine by MainLoop
+// (see RunOnMain), for teardown that must happen on the main thread
+// (e.g. GUI toolkits, some CGO SDKs). MainLoop must be running: it keeps
+// running until Wg is done, which includes the hooks. If MainLoop has
+// already returned, the hook is not run and an error is recorded.
+func WithMainThread() HookOption {
+	return func(h *hook) {
+		h.mainThread = true
+	}
+}
+
+// WithLockedThread makes the hook run on a new goroutine locked to its own
+// OS thread (see runtime.LockOSThread), for cleanups of CGO resources with
+// thread affinity. The thread is terminated when the hook returns, so
+// thread state changed by the hook does not leak to other goroutines.
+func WithLockedThread() HookOption {
+	return func(h *hook) {
+		h.osThread = true
+	}
+}
+
+// SetClassLimit limits the number of concurrently running hooks of the given
+// resource class to n. n <= 0 means no limit.
+//
+// SetClassLimit must be called before shutdown is initiated.
+func SetClassLimit(class string, n int) { std.SetClassLimit(class, n) }
+
+// SetClassLimit limits the number of concurrently running hooks of the given
+// resource class. See the package-level SetClassLimit.
+func (m *Manager) SetClassLimit(class string, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if n <= 0 {
+		delete(m.classLimits, class)
+	} else {
+		m.classLimits[class] = n
+	}
+}
+
+// newHook creates a new hook.
+func newHook(f func() error, prio int, opts []HookOption) hook {
+	h := hook{prio: prio, f: f}
+	for _, opt := range opts {
+		opt(&h)
+	}
+	return h
+}
+
+// String returns the name of the hook.
+func (h hook) String() string {
+	if h.name == "" {
+		return "(unnamed)"
+	}
+	return h.name
+}
+
+// effectiveTimeout returns the timeout of the hook, taking the best-effort
+// timeout of m into account. 0 means no timeout.
+func (h hook) effectiveTimeout(m *Manager) time.Duration {
+	if h.bestEffort && (h.timeout <= 0 || h.timeout > m.bestEffortTimeout) {
+		return m.bestEffortTimeout
+	}
+	return h.timeout
+}
+
+// noErr wraps f into a function returning a nil error.
+func noErr(f func()) func() error {
+	return func() error {
+		f()
+		return nil
+	}
+}
+
+// run runs the hook in phase p, respecting its timeout. sems holds the
+// semaphores of limited resource classes. Errors of the hook (including panics,
+// timing out and being skipped or abandoned due to escalation) and its drain time
+// (see DrainTimes) are recorded in m. The run is traced as a region of the trace task of ctx.
+func (h hook) run(ctx context.Context, m *Manager, p Phase, sems map[string]chan struct{}) {
+	defer m.hookCompleted()
+	defer trace.StartRegion(ctx, "shutdown hook "+h.String()).End()
+
+	start := time.Now()
+	m.mu.Lock()
+	if m.firstHookAt.IsZero() {
+		m.firstHookAt = start
+	}
+	m.hookStatuses[h.idx].State = HookRunning
+	m.mu.Unlock()
+	timedOut, err := h.runErr(m, sems)
+	d := time.Since(start)
+	m.mu.Lock()
+	m.drainTimes = append(m.drainTimes, DrainTime{Name: h.String(), Hook: true, Duration: d})
+	hs := &m.hookStatuses[h.idx]
+	hs.DurationSeconds = d.Seconds()
+	if err != nil {
+		hs.State, hs.Error = HookFailed, err.Error()
+		var pe *panicError
+		if errors.As(err, &pe) {
+			hs.Stack = string(pe.stack)
+		}
+	} else {
+		hs.State = HookDone
+	}
+	m.mu.Unlock()
+	if err != nil {
+		if h.bestEffort {
+			m.warnf("Best-effort shutdown hook %v failed: %v", h, err)
+			return
+		}
+		he := &HookError{
+			Hook:     h.String(),
+			Phase:    p,
+			Duration: d,
+			TimedOut: timedOut,
+			Err:      err,
+		}
+		var pe *panicError
+		if errors.As(err, &pe) {
+			he.Stack = string(pe.stack)
+		}
+		m.mu.Lock()
+		m.hookErrors = append(m.hookErrors, he)
+		m.mu.Unlock()
+	}
+}
+
+// runErr runs the hook and returns whether it timed out, and its error.
+func (h hook) runErr(m *Manager, sems map[string]chan struct{}) (timedOut bool, err error) {
+	// Non-critical hooks are skipped and abandoned when shutdown is escalated.
+	var hurry <-chan struct{}
+	if !h.critical {
+		hurry = m.hurry
+	}
+
+	if sem := sems[h.class]; sem != nil {
+		select {
+		case sem <- struct{}{}:
+			// Released when the hook completes or times out (a timed out hook is
+			// not waited for anymore, so it doesn't hold its slot).
+			defer func() { <-sem }()
+		case <-hurry:
+		}
+	}
+	select {
+	case <-hurry:
+		m.warnf("Skipping shutdown hook %v (shutdown escalated).", h)
+		return false, errSkipped
+	default:
+	}
+
+	timeout := h.effectiveTimeout(m)
+
+	if timeout <= 0 && hurry == nil {
+		return false, h.call(m)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- h.call(m)
+	}()
+
+	var timeoutC <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		timeoutC = t.C
+	}
+
+	select {
+	case err := <-errCh:
+		return false, err
+	case <-timeoutC:
+		m.warnf("Shutdown hook %v timed out after %v, moving on.", h, timeout)
+		return true, fmt.Errorf("timed out after %v", timeout)
+	case <-hurry:
+		m.warnf("Abandoning shutdown hook %v (shutdown escalated).", h)
+		return false, errAbandoned
+	}
+}
+
+// call calls the hook function on the main goroutine or on a locked OS thread
+// if the hook asks so (see WithMainThread and WithLockedThread), else on the
+// current goroutine.
+func (h hook) call(m *Manager) (err error) {
+	switch {
+	case h.mainThread:
+		if !m.RunOnMain(func() { err = h.callRecover(m) }) {
+			return errNoMainLoop
+		}
+		return err
+	case h.osThread:
+		errCh := make(chan error, 1)
+		go func() {
+			// Not unlocked: the thread is terminated when the goroutine exits.
+			runtime.LockOSThread()
+			errCh <- h.callRecover(m)
+		}()
+		return <-errCh
+	}
+	return h.callRecover(m)
+}
+
+// callRecover calls the hook function, recovering from a panic, so a panicking
+// hook doesn't abort the rest of the shutdown. The panic is logged with its
+// stack, and returned as an error.
+func (h hook) callRecover(m *Manager) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			pe := &panicError{value: r, stack: debug.Stack()}
+			m.errorf("Shutdown hook %v panicked: %v\n%s", h, r, pe.stack)
+			err = pe
+		}
+	}()
+
+	return h.f()
+}
+
+// panicError is the error of a panicking hook.
+type panicError struct {
+	value any    // value passed to panic
+	stack []byte // stack trace of the panic
+}
+
+// Error returns the error message, e.g. "panic: boom".
+func (e *panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+// OnShutdown registers f as a shutdown hook, to be run when shutdown is
+// initiated. It may be called from anywhere in the app (e.g. where the
+// resource to be cleaned up is created).
+//
+// Hooks are run sequentially in registration order (see OnShutdownPriority
+// for ordering across packages) in PhaseStop.
+//
+// Hooks registered before Context is cancelled are run, even if shutdown has
+// already been initiated (e.g. while the broadcast is delayed by Hold).
+// Hooks registered later are not run, a warning is logged instead.
+func OnShutdown(f func(), opts ...HookOption) { std.OnShutdown(f, opts...) }
+
+// OnShutdown registers f as a shutdown hook. See the package-level OnShutdown.
+func (m *Manager) OnShutdown(f func(), opts ...HookOption) {
+	m.OnShutdownPriority(0, f, opts...)
+}
+
+// OnShutdownPriority registers f as a shutdown hook with the given priority.
+// Hooks with higher priority are run before hooks with lower priority,
+// regardless of registration order. Hooks with equal priority are run in
+// order of their sequence numbers (see WithSequence), then in registration
+// order. Hooks registered with OnShutdown have priority 0.
+//
+// For example, stopping to accept traffic should have a higher priority than
+// closing DB pools.
+func OnShutdownPriority(prio int, f func(), opts ...HookOption) {
+	std.OnShutdownPriority(prio, f, opts...)
+}
+
+// OnShutdownPriority registers f as a shutdown hook with the given priority.
+// See the package-level OnShutdownPriority.
+func (m *Manager) OnShutdownPriority(prio int, f func(), opts ...HookOption) {
+	m.addHook(newHook(noErr(f), prio, opts))
+}
+
+// OnShutdownError registers f as a shutdown hook like OnShutdown,
+// but f may return an error, which is recorded (see Errors).
+func OnShutdownError(f func() error, opts ...HookOption) { std.OnShutdownError(f, opts...) }
+
+// OnShutdownError registers f as a shutdown hook returning an error.
+// See the package-level OnShutdownError.
+func (m *Manager) OnShutdownError(f func() error, opts ...HookOption) {
+	m.addHook(newHook(f, 0, opts))
+}
+
+// addHook adds h to the hooks.
+func (m *Manager) addHook(h hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.refuseHook(h) {
+		m.hooks = append(m.hooks, h)
+	}
+}
+
+// refuseHook tells if h can't be registered anymore because Context has been
+// cancelled (the hooks to run have been taken), in which case it is logged.
+// m.mu must be held.
+func (m *Manager) refuseHook(h hook) bool {
+	if !m.hooksTaken {
+		return false
+	}
+	m.warnf("Shutdown hook %v registered after Context was cancelled, not running it.", h)
+	return true
+}
+
+// Defer registers f to be run on shutdown, like defer but at application scope:
+// deferred functions are run in reverse registration order (LIFO), after the
+// hooks registered with OnShutdown.
+//
+// This matches how resources are typically acquired and released: if the DB
+// is opened first, then the cache, then the server, they are released in the
+// opposite order.
+func Defer(f func(), opts ...HookOption) { std.Defer(f, opts...) }
+
+// Defer registers f to be run on shutdown. See the package-level Defer.
+func (m *Manager) Defer(f func(), opts ...HookOption) {
+	h := newHook(noErr(f), 0, opts)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.refuseHook(h) {
+		m.deferred = append(m.deferred, h)
+	}
+}
+
+// Pair runs setup, and registers the returned cleanup function with Defer,
+// keeping acquisition and release of a resource adjacent in code:
+//
+//	err := shutdown.Pair(func() (func(), error) {
+//		db, err := sql.Open("postgres", dsn)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return func() { db.Close() }, nil
+//	})
+//
+// If setup returns an error, it is returned, and cleanup is not registered.
+// A nil cleanup is not registered either.
+func Pair(setup func() (cleanup func(), err error), opts ...HookOption) error {
+	return std.Pair(setup, opts...)
+}
+
+// Pair runs setup, and registers the returned cleanup function with Defer.
+// See the package-level Pair.
+func (m *Manager) Pair(setup func() (cleanup func(), err error), opts ...HookOption) error {
+	cleanup, err := setup()
+	if err != nil {
+		return err
+	}
+	if cleanup != nil {
+		m.Defer(cleanup, opts...)
+	}
+	return nil
+}
+
+// OnPhase registers f as a hook of the given phase. Phases are executed
+// sequentially (a phase starts when all hooks of the previous phase have
+// completed), while hooks within a phase are run concurrently.
+//
+// In PhaseStop, hooks registered with OnPhase are run concurrently with the
+// (sequential) hooks registered with OnShutdown, OnShutdownPriority and Defer.
+//
+// OnPhase panics if phase is not one of the defined phases.
+func OnPhase(phase Phase, f func(), opts ...HookOption) { std.OnPhase(phase, f, opts...) }
+
+// OnPhase registers f as a hook of the given phase. See the package-level OnPhase.
+func (m *Manager) OnPhase(phase Phase, f func(), opts ...HookOption) {
+	m.OnPhaseError(phase, noErr(f), opts...)
+}
+
+// OnPhaseError registers f as a hook of the given phase like OnPhase,
+// but f may return an error, which is recorded (see Errors).
+func OnPhaseError(phase Phase, f func() error, opts ...HookOption) {
+	std.OnPhaseError(phase, f, opts...)
+}
+
+// OnPhaseError registers f as a hook of the given phase returning an error.
+// See the package-level OnPhaseError.
+func (m *Manager) OnPhaseError(phase Phase, f func() error, opts ...HookOption) {
+	if !validPhase(phase) {
+		panic(fmt.Sprintf("shutdown: unknown phase %q", phase))
+	}
+	h := newHook(f, 0, opts)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.refuseHook(h) {
+		m.phaseHooks[phase] = append(m.phaseHooks[phase], h)
+	}
+}
+
+// OnPhaseStart registers f to be called when phase starts, before the hooks of
+// the phase are run. OnPhaseEnd registers f to be called when phase ends, after
+// all hooks of the phase have completed. They are meant for cross-cutting
+// concerns such as metrics, notifications or gating.
+//
+// Callbacks of a phase are called sequentially, in the order of registration,
+// and they delay the phase: they should return quickly. A panicking callback is
+// logged and does not stop the shutdown. Callbacks of a skipped phase (e.g.
+// PhaseCgo without hooks) are not called.
+//
+// OnPhaseStart panics if phase is not one of the defined phases.
+func OnPhaseStart(phase Phase, f func()) { std.OnPhaseStart(phase, f) }
+
+// OnPhaseStart registers f to be called when phase starts.
+// See the package-level OnPhaseStart.
+func (m *Manager) OnPhaseStart(phase Phase, f func()) {
+	m.addPhaseCallback(m.phaseStarts, phase, f)
+}
+
+// OnPhaseEnd registers f to be called when phase ends: after all hooks of the
+// phase have completed (or have been abandoned, e.g. due to timing out),
+// before the next phase starts. It is meant for the same cross-cutting
+// concerns as OnPhaseStart, e.g. recording how long a phase took.
+//
+// Callbacks of a phase are called sequentially, in the order of registration,
+// and they delay the next phase: they should return quickly. A panicking
+// callback is logged and does not stop the shutdown. Callbacks of a skipped
+// phase (e.g. PhaseCgo without hooks, or when exit has already begun) are not
+// called.
+//
+// OnPhaseEnd panics if phase is not one of the defined phases.
+func OnPhaseEnd(phase Phase, f func()) { std.OnPhaseEnd(phase, f) }
+
+// OnPhaseEnd registers f to be called when phase ends.
+// See the package-level OnPhaseEnd.
+func (m *Manager) OnPhaseEnd(phase Phase, f func()) {
+	m.addPhaseCallback(m.phaseEnds, phase, f)
+}
+
+// addPhaseCallback adds f to the callbacks of phase in cbs.
+func (m *Manager) addPhaseCallback(cbs map[Phase][]func(), phase Phase, f func()) {
+	if !validPhase(phase) {
+		panic(fmt.Sprintf("shutdown: unknown phase %q", phase))
+	}
+
+	m.mu.Lock()
+	cbs[phase] = append(cbs[phase], f)
+	m.mu.Unlock()
+}
+
+// callPhaseCallbacks calls the callbacks of phase p in cbs.
+// event is used in logs.
+func (m *Manager) callPhaseCallbacks(cbs map[Phase][]func(), p Phase, event string) {
+	m.mu.Lock()
+	fs := cbs[p]
+	m.mu.Unlock()
+
+	for i, f := range fs {
+		m.callRecover(fmt.Sprintf("%s phase %s callback #%d", p, event, i+1), f)
+	}
+}
+
+// validPhase tells if phase is one of the defined phases.
+func validPhase(phase Phase) bool {
+	for _, p := range phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// runHooks runs the phases with their hooks, and closes m.hooksDone
+// and calls m.Wg.Done() when done.
+func (m *Manager) runHooks() {
+	defer m.Wg.Done()
+	defer close(m.hooksDone)
+
+	m.mu.Lock()
+	hs := append([]hook(nil), m.hooks...)
+	ds := m.deferred
+	phs := map[Phase][]hook{}
+	for p, phHooks := range m.phaseHooks {
+		phs[p] = phHooks
+	}
+	sems := map[string]chan struct{}{}
+	for class, n := range m.classLimits {
+		sems[class] = make(chan struct{}, n)
+	}
+	m.mu.Unlock()
+
+	sort.SliceStable(hs, func(i, j int) bool {
+		if hs[i].prio != hs[j].prio {
+			return hs[i].prio > hs[j].prio
+		}
+		return hs[i].seq < hs[j].seq
+	})
+	// Deferred hooks are run in reverse registration order.
+	rds := make([]hook, len(ds))
+	for i, h := range ds {
+		rds[len(ds)-1-i] = h
+	}
+
+	// Index the hooks in run order to track their statuses (see Status).
+	var statuses []HookStatus
+	index := func(p Phase, hooks []hook) []hook {
+		hooks = append([]hook(nil), hooks...)
+		for i := range hooks {
+			hooks[i].idx = len(statuses)
+			statuses = append(statuses, HookStatus{Name: hooks[i].String(), Phase: p, State: HookPending})
+		}
+		return hooks
+	}
+	for _, p := range phases {
+		phs[p] = index(p, phs[p])
+		if p == PhaseStop {
+			hs = index(p, hs)
+			rds = index(p, rds)
+		}
+	}
+	m.mu.Lock()
+	m.hooksTotal = len(statuses)
+	m.hookStatuses = statuses
+	m.mu.Unlock()
+
+	// Trace the shutdown (visible with go tool trace): a task for the shutdown,
+	// a region for each phase and each hook.
+	ctx, task := trace.NewTask(context.Background(), "shutdown")
+	defer task.End()
+	trace.Log(ctx, "reason", m.Reason())
+
+	for _, p := range phases {
+		m.mu.Lock()
+		m.phase = p
+		m.mu.Unlock()
+		m.updateStatusFile()
+
+		if p == PhaseCgo {
+			if len(phs[p]) == 0 {
+				continue
+			}
+			if !m.beginCgo() {
+				m.warnf("Exit has begun, skipping cgo cleanup.")
+				continue
+			}
+		}
+
+		region := trace.StartRegion(ctx, "shutdown phase "+string(p))
+		m.callPhaseCallbacks(m.phaseStarts, p, "start")
+
+		wg := &sync.WaitGroup{}
+		for _, h := range phs[p] {
+			wg.Add(1)
+			go func(h hook) {
+				defer wg.Done()
+				h.run(ctx, m, p, sems)
+			}(h)
+		}
+
+		if p == PhaseStop {
+			for _, h := range hs {
+				h.run(ctx, m, p, sems)
+			}
+			for _, h := range rds {
+				h.run(ctx, m, p, sems)
+			}
+		}
+
+		wg.Wait()
+
+		m.callPhaseCallbacks(m.phaseEnds, p, "end")
+		region.End()
+
+		if p == PhaseCgo {
+			m.endCgo()
+		}
+	}
+}
+
+// hookCompleted records the completion of a hook.
+func (m *Manager) hookCompleted() {
+	m.mu.Lock()
+	m.hooksCompleted++
+	m.mu.Unlock()
+	m.updateStatusFile()
+}
+
+// Errors returns the errors of shutdown hooks: errors returned by hooks,
+// panics and timeouts. The errors are of type *HookError.
+// If all hooks have completed (e.g. after the final Wait
+// has returned) and Errors returns nil, cleanup fully succeeded.
+func Errors() []error { return std.Errors() }
+
+// Errors returns the errors of shutdown hooks. See the package-level Errors.
+func (m *Manager) Errors() []error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.hookErrors == nil {
+		return nil
+	}
+	errs := make([]error, len(m.hookErrors))
+	for i, e := range m.hookErrors {
+		errs[i] = e
+	}
+	return errs
+}
+
+// HooksErr returns the errors of shutdown hooks as a HookErrors multi-error,
+// or nil if there are none. See Errors.
+//
+// Use errors.As to react to specific failed hooks, e.g.:
+//
+//	var hookErr *shutdown.HookError
+//	if errors.As(shutdown.HooksErr(), &hookErr) && hookErr.TimedOut {
+//		// ...
+//	}
+func HooksErr() error { return std.HooksErr() }
+
+// HooksErr returns the errors of shutdown hooks as a multi-error.
+// See the package-level HooksErr.
+func (m *Manager) HooksErr() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.hookErrors) == 0 {
+		return nil
+	}
+	errs := make(HookErrors, len(m.hookErrors))
+	copy(errs, m.hookErrors)
+	return errs
+}
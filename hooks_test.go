@@ -0,0 +1,328 @@
+package shutdown
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder records events concurrently.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+}
+
+func (r *recorder) addFunc(event string) func() {
+	return func() { r.add(event) }
+}
+
+func (r *recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return strings.Join(r.events, ",")
+}
+
+func TestPhaseCallbacks(t *testing.T) {
+	m := newTestManager()
+	r := &recorder{}
+	for _, p := range []Phase{PhaseDrain, PhaseStop, PhaseCgo} {
+		m.OnPhaseStart(p, r.addFunc(string(p)+"-start"))
+		m.OnPhaseEnd(p, r.addFunc(string(p)+"-end"))
+	}
+	m.OnPhaseStart(PhaseDrain, func() { panic("boom") })
+	m.OnPhase(PhaseDrain, r.addFunc("drain-hook"))
+	m.OnShutdown(r.addFunc("stop-hook"))
+
+	m.Close()
+
+	// PhaseCgo has no hooks, so it is skipped along with its callbacks.
+	want := "drain-start,drain-hook,drain-end,stop-start,stop-hook,stop-end"
+	if got := r.String(); got != want {
+		t.Errorf("events are %q, want %q", got, want)
+	}
+}
+
+func TestPhaseCallbackUnknownPhase(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("OnPhaseStart did not panic on unknown phase")
+		}
+	}()
+	newTestManager().OnPhaseStart("unknown", func() {})
+}
+
+func TestHookOrder(t *testing.T) {
+	m := newTestManager()
+	r := &recorder{}
+	m.OnShutdown(r.addFunc("a"))
+	m.OnShutdownPriority(10, r.addFunc("prio"))
+	m.Defer(r.addFunc("defer1"))
+	m.OnShutdown(r.addFunc("b"))
+	m.Defer(r.addFunc("defer2"))
+	m.OnPhase(PhaseCleanup, r.addFunc("cleanup"))
+	m.OnPhase(PhaseDrain, r.addFunc("drain"))
+
+	m.Close()
+
+	want := "drain,prio,a,b,defer2,defer1,cleanup"
+	if got := r.String(); got != want {
+		t.Errorf("events are %q, want %q", got, want)
+	}
+
+	// Hooks registered after shutdown has been initiated are not run.
+	m.OnShutdown(r.addFunc("late"))
+	if got := r.String(); got != want {
+		t.Errorf("events are %q, want %q", got, want)
+	}
+}
+
+func TestHookSequence(t *testing.T) {
+	m := newTestManager()
+	r := &recorder{}
+
+	// Registered concurrently, in random order.
+	var wg sync.WaitGroup
+	for _, h := range []struct {
+		name string
+		seq  int
+	}{{"c", 3}, {"a", 1}, {"b", 2}} {
+		wg.Add(1)
+		go func(name string, seq int) {
+			defer wg.Done()
+			m.OnShutdown(r.addFunc(name), WithSequence(seq))
+		}(h.name, h.seq)
+	}
+	wg.Wait()
+	m.OnShutdownPriority(10, r.addFunc("prio"), WithSequence(100)) // Priority comes first.
+	m.OnShutdown(r.addFunc("unsequenced"))
+
+	m.Close()
+
+	if got, want := r.String(), "prio,unsequenced,a,b,c"; got != want {
+		t.Errorf("events are %q, want %q", got, want)
+	}
+}
+
+func TestHookErrors(t *testing.T) {
+	m := newTestManager()
+	errFailed := errors.New("failed")
+	block := make(chan struct{})
+	defer close(block)
+
+	m.OnShutdownError(func() error { return errFailed }, WithName("failing"))
+	m.OnShutdown(func() { panic("boom") }, WithName("panicking"))
+	m.OnShutdown(func() { <-block }, WithName("stuck"), WithTimeout(10*time.Millisecond))
+	m.OnShutdownError(func() error { return errFailed }, WithName("best-effort"), WithBestEffort())
+	m.OnShutdown(func() {}, WithName("ok"))
+
+	m.Close()
+
+	errs := m.Errors()
+	if len(errs) != 3 {
+		t.Fatalf("got %d errors, want 3: %v", len(errs), errs)
+	}
+	for i, want := range []struct {
+		hook     string
+		timedOut bool
+	}{{"failing", false}, {"panicking", false}, {"stuck", true}} {
+		var he *HookError
+		if !errors.As(errs[i], &he) {
+			t.Fatalf("error #%d is of type %T, want *HookError", i, errs[i])
+		}
+		if he.Hook != want.hook || he.TimedOut != want.timedOut || he.Phase != PhaseStop {
+			t.Errorf("error #%d is %+v, want hook %q, timed out: %t in phase %s", i, he, want.hook, want.timedOut, PhaseStop)
+		}
+	}
+	if !errors.Is(m.HooksErr(), errFailed) {
+		t.Errorf("HooksErr is %v, want it to wrap %v", m.HooksErr(), errFailed)
+	}
+
+	// The stack of the panic is kept in the error, the aggregate error and Status.
+	var he *HookError
+	errors.As(errs[1], &he)
+	if he.Err.Error() != "panic: boom" || !strings.Contains(he.Stack, "TestHookErrors") {
+		t.Errorf("panic error is %v with stack %q, want panic: boom with the stack of the hook", he.Err, he.Stack)
+	}
+	if errs[0].(*HookError).Stack != "" {
+		t.Error("non-panic error has a stack")
+	}
+	if msg := m.HooksErr().Error(); !strings.Contains(msg, "shutdown hook panicking: panic: boom\ngoroutine ") {
+		t.Errorf("HooksErr is %q, want it to contain the stack of the panic", msg)
+	}
+	for _, hs := range m.Status().Hooks {
+		if (hs.Name == "panicking") != (hs.Stack != "") {
+			t.Errorf("hook status %+v, want stack only for the panicking hook", hs)
+		}
+	}
+}
+
+func TestEscalateCritical(t *testing.T) {
+	m := newTestManager()
+	r := &recorder{}
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+
+	m.OnShutdown(func() { close(started); <-release }, WithName("slow"))
+	m.OnShutdown(r.addFunc("skipped"), WithName("skipped"))
+	m.OnShutdown(r.addFunc("critical"), WithName("critical"), WithCritical())
+
+	m.InitiateManual()
+	<-started
+	m.escalateCritical()
+	m.Wait()
+
+	if got, want := r.String(), "critical"; got != want {
+		t.Errorf("events are %q, want %q", got, want)
+	}
+	errs := m.Errors()
+	if len(errs) != 2 || !errors.Is(errs[0], errAbandoned) || !errors.Is(errs[1], errSkipped) {
+		t.Errorf("errors are %v, want [abandoned skipped]", errs)
+	}
+}
+
+func TestClassLimit(t *testing.T) {
+	m := newTestManager()
+	m.SetClassLimit("disk", 2)
+	m.SetClassLimit("network", 1)
+	m.SetClassLimit("network", 0) // No limit.
+
+	var mu sync.Mutex
+	running, maxRunning := map[string]int{}, map[string]int{}
+	hook := func(class string) func() {
+		return func() {
+			mu.Lock()
+			running[class]++
+			if running[class] > maxRunning[class] {
+				maxRunning[class] = running[class]
+			}
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			running[class]--
+			mu.Unlock()
+		}
+	}
+	for i := 0; i < 5; i++ {
+		m.OnPhase(PhaseCleanup, hook("disk"), WithClass("disk"))
+		m.OnPhase(PhaseCleanup, hook("network"), WithClass("network"))
+	}
+
+	m.Close()
+	if maxRunning["disk"] != 2 {
+		t.Errorf("max %d disk hooks ran concurrently, want 2", maxRunning["disk"])
+	}
+	if maxRunning["network"] != 5 {
+		t.Errorf("max %d network hooks ran concurrently, want 5 (no limit)", maxRunning["network"])
+	}
+}
+
+func TestClassLimitTimedOutHook(t *testing.T) {
+	m := newTestManager()
+	m.SetClassLimit("disk", 1)
+
+	release := make(chan struct{})
+	defer close(release)
+	ran := false
+	m.OnPhase(PhaseCleanup, func() { <-release }, WithClass("disk"), WithTimeout(20*time.Millisecond))
+	m.OnPhase(PhaseCleanup, func() { ran = true }, WithClass("disk"))
+
+	// A timed out hook doesn't hold its slot.
+	m.Close()
+	if !ran {
+		t.Error("hook waiting for the slot of a timed out hook was not run")
+	}
+}
+
+func TestWithLockedThread(t *testing.T) {
+	m := newTestManager()
+
+	ran := false
+	m.OnShutdown(func() { ran = true }, WithLockedThread())
+	m.OnShutdownError(func() error { return errors.New("close failed") }, WithLockedThread(), WithName("cgo"))
+	m.Close()
+
+	if !ran {
+		t.Error("hook not run")
+	}
+	if errs := m.Errors(); len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "shutdown hook cgo:") {
+		t.Errorf("got hook errors %v, want the error of the cgo hook", errs)
+	}
+}
+
+func TestHookRegisteredAfterInitiation(t *testing.T) {
+	m := newTestManager()
+	release := m.Hold()
+	m.InitiateManual()
+
+	// Registered after initiation, but before Context is cancelled: run.
+	var held, late bool
+	m.OnShutdown(func() { held = true })
+
+	block := make(chan struct{})
+	m.OnPhase(PhaseDrain, func() { <-block })
+	release()
+	<-m.C
+
+	// Registered after Context is cancelled: not run.
+	m.OnShutdown(func() { late = true })
+	m.Defer(func() { late = true })
+	m.OnPhase(PhaseCleanup, func() { late = true })
+	close(block)
+	m.Wait()
+
+	if !held {
+		t.Error("hook registered while held not run")
+	}
+	if late {
+		t.Error("hook registered after Context was cancelled run")
+	}
+}
+
+func TestPair(t *testing.T) {
+	m := newTestManager()
+	r := &recorder{}
+
+	acquire := func(name string) func() (func(), error) {
+		return func() (func(), error) {
+			r.add("open " + name)
+			return r.addFunc("close " + name), nil
+		}
+	}
+	if err := m.Pair(acquire("db")); err != nil {
+		t.Errorf("Pair returned %v, want nil", err)
+	}
+	if err := m.Pair(acquire("cache")); err != nil {
+		t.Errorf("Pair returned %v, want nil", err)
+	}
+
+	// A failed setup is returned, its cleanup is not registered.
+	errSetup := errors.New("dial failed")
+	err := m.Pair(func() (func(), error) {
+		return func() { t.Error("cleanup of failed setup run") }, errSetup
+	})
+	if !errors.Is(err, errSetup) {
+		t.Errorf("Pair returned %v, want %v", err, errSetup)
+	}
+	// A nil cleanup is not registered.
+	if err := m.Pair(func() (func(), error) { return nil, nil }); err != nil {
+		t.Errorf("Pair returned %v, want nil", err)
+	}
+
+	if got, want := r.String(), "open db,open cache"; got != want {
+		t.Errorf("events before shutdown are %q, want %q", got, want)
+	}
+	m.Close()
+
+	// Released in reverse order of acquisition.
+	if got, want := r.String(), "open db,open cache,close cache,close db"; got != want {
+		t.Errorf("events are %q, want %q", got, want)
+	}
+}
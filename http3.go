@@ -0,0 +1,57 @@
+package shutdown
+
+import (
+	"context"
+	"net"
+)
+
+// HTTP3Server is the subset of the methods of http3.Server (of quic-go) used by
+// HTTP3. It is declared here so this package doesn't depend on quic-go.
+type HTTP3Server interface {
+	Serve(conn net.PacketConn) error
+	Shutdown(ctx context.Context) error
+	Close() error
+}
+
+// HTTP3 adapts an http3.Server serving on the UDP socket conn to a
+// GracefulServer, so it can be used with Manage:
+//
+//	conn, err := net.ListenPacket("udp", ":443")
+//	// handle err
+//	shutdown.Manage("HTTP/3 server", shutdown.HTTP3(srv, conn))
+//
+// On shutdown the server sends GOAWAY frames and waits for open streams to
+// finish until the shutdown context expires, then the UDP socket is closed.
+// The returned GracefulServer also implements io.Closer for forceful shutdown.
+func HTTP3(srv HTTP3Server, conn net.PacketConn) GracefulServer {
+	return http3Server{srv: srv, conn: conn}
+}
+
+// http3Server implements GracefulServer and io.Closer for an HTTP3Server.
+type http3Server struct {
+	srv  HTTP3Server
+	conn net.PacketConn
+}
+
+// Serve implements GracefulServer.
+func (s http3Server) Serve() error {
+	return s.srv.Serve(s.conn)
+}
+
+// Shutdown implements GracefulServer. The UDP socket is only closed if the
+// graceful shutdown succeeds (else Close should be called).
+func (s http3Server) Shutdown(ctx context.Context) error {
+	if err := s.srv.Shutdown(ctx); err != nil {
+		return err
+	}
+	return s.conn.Close()
+}
+
+// Close closes the server and the UDP socket.
+func (s http3Server) Close() error {
+	err := s.srv.Close()
+	if err2 := s.conn.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
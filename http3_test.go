@@ -0,0 +1,90 @@
+package shutdown
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeHTTP3Server is an HTTP3Server with open streams finishing when
+// streamsDone is closed.
+type fakeHTTP3Server struct {
+	stopped     chan struct{}
+	streamsDone chan struct{}
+	closed      atomic.Bool
+}
+
+func newFakeHTTP3Server() *fakeHTTP3Server {
+	return &fakeHTTP3Server{stopped: make(chan struct{}), streamsDone: make(chan struct{})}
+}
+
+func (s *fakeHTTP3Server) Serve(conn net.PacketConn) error {
+	<-s.stopped
+	return nil
+}
+
+func (s *fakeHTTP3Server) Shutdown(ctx context.Context) error {
+	close(s.stopped)
+	select {
+	case <-s.streamsDone:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *fakeHTTP3Server) Close() error {
+	s.closed.Store(true)
+	return nil
+}
+
+// udpConn returns a UDP socket for tests.
+func udpConn(t *testing.T) net.PacketConn {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("can't listen on UDP: %v", err)
+	}
+	return conn
+}
+
+// connClosed tells if conn has been closed.
+func connClosed(conn net.PacketConn) bool {
+	_, err := conn.WriteTo([]byte{0}, conn.LocalAddr())
+	return err != nil
+}
+
+func TestHTTP3(t *testing.T) {
+	m := newTestManager()
+	srv, conn := newFakeHTTP3Server(), udpConn(t)
+	m.Manage("http3", HTTP3(srv, conn))
+	time.AfterFunc(20*time.Millisecond, func() { close(srv.streamsDone) })
+
+	m.InitiateManual()
+	m.Wait()
+
+	if !connClosed(conn) {
+		t.Error("UDP socket not closed after graceful shutdown")
+	}
+	if srv.closed.Load() {
+		t.Error("server closed forcefully after graceful shutdown")
+	}
+}
+
+func TestHTTP3Timeout(t *testing.T) {
+	m := newTestManager(WithServerShutdownTimeout(20 * time.Millisecond))
+	srv, conn := newFakeHTTP3Server(), udpConn(t)
+	m.Manage("http3", HTTP3(srv, conn)) // Streams never finish.
+
+	m.InitiateManual()
+	m.Wait()
+
+	if !srv.closed.Load() {
+		t.Error("server not closed forcefully after the shutdown timeout")
+	}
+	if !connClosed(conn) {
+		t.Error("UDP socket not closed after forceful shutdown")
+	}
+}
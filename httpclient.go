@@ -0,0 +1,116 @@
+package shutdown
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithHTTPClientDrainTimeout sets the max time to wait for in-flight outbound
+// requests of clients registered with TrackHTTPClient. The default is 20 seconds.
+func WithHTTPClientDrainTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.httpClientDrainTimeout = timeout
+	}
+}
+
+// TrackHTTPClient registers client for connection pool drain in PhaseCleanup:
+// in-flight outbound requests made with client are tracked, and they are waited
+// for (no longer than the drain timeout, see WithHTTPClientDrainTimeout), and
+// then the client's idle connections are closed. Draining in PhaseCleanup lets hooks of earlier phases
+// make final outbound requests.
+//
+// A request is in-flight until its response body is closed (or until the
+// request fails).
+//
+// TrackHTTPClient wraps client.Transport, so it should be called before the
+// client is used. If client.Transport is nil, a clone of http.DefaultTransport
+// is used, so closing the idle connections of client doesn't affect other
+// clients sharing http.DefaultTransport.
+func TrackHTTPClient(client *http.Client) { std.TrackHTTPClient(client) }
+
+// TrackHTTPClient registers client for connection pool drain.
+// See the package-level TrackHTTPClient.
+func (m *Manager) TrackHTTPClient(client *http.Client) {
+	rt := client.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+		if t, ok := rt.(*http.Transport); ok {
+			rt = t.Clone()
+		}
+	}
+	tt := &trackingTransport{rt: rt}
+	client.Transport = tt
+
+	m.OnPhase(PhaseCleanup, func() {
+		if n := tt.count(); n > 0 {
+			m.logf("Waiting for %d in-flight outbound HTTP request(s)...", n)
+			ctx, cancel := context.WithTimeout(context.Background(), m.httpClientDrainTimeout)
+			err := waitFor(ctx, nil, 10*time.Millisecond, func() bool { return tt.count() == 0 })
+			cancel()
+			if err != nil {
+				m.warnf("Timed out waiting for %d in-flight outbound HTTP request(s).", tt.count())
+			}
+		}
+
+		client.CloseIdleConnections()
+	}, WithName("HTTP client drain"))
+}
+
+// trackingTransport is an http.RoundTripper tracking in-flight requests.
+type trackingTransport struct {
+	rt http.RoundTripper
+
+	mu sync.Mutex
+	n  int // Number of in-flight requests
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *trackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.add(1)
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		t.add(-1)
+		return resp, err
+	}
+	resp.Body = &trackingBody{ReadCloser: resp.Body, done: func() { t.add(-1) }}
+	return resp, nil
+}
+
+// CloseIdleConnections closes idle connections of the wrapped transport
+// if it supports it.
+func (t *trackingTransport) CloseIdleConnections() {
+	if c, ok := t.rt.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+// add adds delta to the in-flight counter.
+func (t *trackingTransport) add(delta int) {
+	t.mu.Lock()
+	t.n += delta
+	t.mu.Unlock()
+}
+
+// count returns the number of in-flight requests.
+func (t *trackingTransport) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.n
+}
+
+// trackingBody is a response body calling done once when closed.
+type trackingBody struct {
+	io.ReadCloser
+	once sync.Once
+	done func()
+}
+
+// Close closes the body and calls done once.
+func (b *trackingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.done)
+	return err
+}
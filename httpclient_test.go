@@ -0,0 +1,76 @@
+package shutdown
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTrackHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	m := newTestManager()
+	client := &http.Client{}
+	m.TrackHTTPClient(client)
+	if tt, ok := client.Transport.(*trackingTransport); !ok || tt.rt == http.DefaultTransport {
+		t.Error("default transport is not cloned")
+	}
+
+	// In-flight request (until its body is closed) when shutdown is initiated.
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bodyClosed := false
+	time.AfterFunc(50*time.Millisecond, func() {
+		io.ReadAll(resp.Body)
+		bodyClosed = true
+		resp.Body.Close()
+	})
+
+	// Hooks of earlier phases may still make requests.
+	var stopErr error
+	m.OnPhase(PhaseStop, func() {
+		resp, err := client.Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		stopErr = err
+	})
+	drained := false
+	m.OnPhaseEnd(PhaseCleanup, func() { drained = bodyClosed })
+
+	m.Close()
+	if stopErr != nil {
+		t.Errorf("request in PhaseStop failed: %v", stopErr)
+	}
+	if !drained {
+		t.Error("in-flight request not waited for")
+	}
+}
+
+func TestTrackHTTPClientTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	m := newTestManager(WithHTTPClientDrainTimeout(50 * time.Millisecond))
+	client := &http.Client{Transport: &http.Transport{}}
+	m.TrackHTTPClient(client)
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() // Never closed during shutdown.
+
+	start := time.Now()
+	m.Close()
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("drain took %v, want about the drain timeout", d)
+	}
+}
@@ -0,0 +1,43 @@
+package shutdown
+
+import "time"
+
+// Latencies holds the latencies of the shutdown path, see ShutdownLatencies.
+type Latencies struct {
+	// SignalToCancel is the time elapsed between receiving the signal that
+	// initiated the shutdown and cancelling Context (closing C). It includes
+	// waiting for holds (see Hold) and handing off leaderships (see
+	// AddLeadership), but not the confirm window (see WithConfirmWindow).
+	// 0 if shutdown was not initiated by a signal, or Context has not been
+	// cancelled yet.
+	SignalToCancel time.Duration
+
+	// CancelToFirstHook is the time elapsed between cancelling Context and the
+	// start of the first shutdown hook. 0 if no hook has been started yet.
+	CancelToFirstHook time.Duration
+}
+
+// ShutdownLatencies returns the latencies of the shutdown path, so it can be
+// verified that the package adds negligible overhead between receiving the
+// signal and running the shutdown hooks.
+func ShutdownLatencies() Latencies { return std.Latencies() }
+
+// Latencies returns the latencies of the shutdown path.
+// See the package-level ShutdownLatencies.
+func (m *Manager) Latencies() Latencies {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.latencies()
+}
+
+// latencies returns the latencies of the shutdown path. m.mu must be held.
+func (m *Manager) latencies() Latencies {
+	var l Latencies
+	if !m.signalAt.IsZero() && !m.cancelledAt.IsZero() {
+		l.SignalToCancel = m.cancelledAt.Sub(m.signalAt)
+	}
+	if !m.cancelledAt.IsZero() && !m.firstHookAt.IsZero() {
+		l.CancelToFirstHook = m.firstHookAt.Sub(m.cancelledAt)
+	}
+	return l
+}
@@ -0,0 +1,45 @@
+package shutdown
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLatencies(t *testing.T) {
+	m := newTestManager()
+	m.OnShutdown(func() {})
+	release := m.Hold()
+	time.AfterFunc(50*time.Millisecond, release)
+
+	src := &signalSource{ch: make(chan os.Signal, 1), stop: make(chan struct{})}
+	m.AddSource(src)
+	src.ch <- syscall.SIGTERM
+	<-m.C
+	m.Wait()
+
+	l := m.Latencies()
+	if l.SignalToCancel < 50*time.Millisecond || l.SignalToCancel > time.Second {
+		t.Errorf("SignalToCancel is %v, want the hold time (50ms)", l.SignalToCancel)
+	}
+	if l.CancelToFirstHook < 0 || l.CancelToFirstHook > time.Second {
+		t.Errorf("CancelToFirstHook is %v, want a small latency", l.CancelToFirstHook)
+	}
+	if st := m.Status(); st.SignalToCancelSeconds != l.SignalToCancel.Seconds() {
+		t.Errorf("Status SignalToCancelSeconds is %v, want %v", st.SignalToCancelSeconds, l.SignalToCancel.Seconds())
+	}
+}
+
+func TestLatenciesManual(t *testing.T) {
+	m := newTestManager()
+	if l := m.Latencies(); l != (Latencies{}) {
+		t.Errorf("Latencies before initiation are %+v, want zero", l)
+	}
+
+	m.Close()
+	// No signal and no hooks.
+	if l := m.Latencies(); l != (Latencies{}) {
+		t.Errorf("Latencies are %+v, want zero", l)
+	}
+}
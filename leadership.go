@@ -0,0 +1,85 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithLeadershipTimeout sets the max time to wait for leadership resignations
+// (see AddLeadership) before the shutdown is broadcast. The default is 10 seconds.
+func WithLeadershipTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.leadershipTimeout = timeout
+	}
+}
+
+// leadership is a registered leadership handle.
+type leadership struct {
+	name   string
+	resign func(ctx context.Context) error
+}
+
+// AddLeadership registers a leadership handle (e.g. a k8s lease or an etcd
+// election) of a leader-elected service. name is used in logs.
+//
+// When shutdown is initiated, resign is called before the shutdown is broadcast
+// (before C is closed and Context is cancelled), so leadership is handed off
+// before workers are stopped, avoiding split-brain during rollouts.
+// resign should return once resignation is confirmed.
+//
+// Registered resign functions are called concurrently, and the broadcast waits
+// for all of them, but no longer than the leadership timeout (see
+// WithLeadershipTimeout). The ctx passed to resign is cancelled when the
+// timeout is exceeded.
+func AddLeadership(name string, resign func(ctx context.Context) error) {
+	std.AddLeadership(name, resign)
+}
+
+// AddLeadership registers a leadership handle. See the package-level AddLeadership.
+func (m *Manager) AddLeadership(name string, resign func(ctx context.Context) error) {
+	m.mu.Lock()
+	m.leaderships = append(m.leaderships, leadership{name: name, resign: resign})
+	m.mu.Unlock()
+}
+
+// resignLeaderships resigns all registered leaderships, and waits for their
+// completion (no longer than m.leadershipTimeout).
+func (m *Manager) resignLeaderships() {
+	m.mu.Lock()
+	ls := m.leaderships
+	m.mu.Unlock()
+
+	if len(ls) == 0 {
+		return
+	}
+
+	m.logf("Resigning %d leadership(s)...", len(ls))
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.leadershipTimeout)
+	defer cancel()
+
+	wg := &sync.WaitGroup{}
+	for _, l := range ls {
+		wg.Add(1)
+		go func(l leadership) {
+			defer wg.Done()
+			defer m.warnBlocking(l.name + " leadership resignation")()
+			if err := l.resign(ctx); err != nil {
+				m.errorf("Failed to resign %s leadership: %v", l.name, err)
+			}
+		}(l)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		m.warnf("Leadership resignation timed out.")
+	}
+}
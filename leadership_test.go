@@ -0,0 +1,58 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLeadershipResignBeforeBroadcast(t *testing.T) {
+	m := newTestManager()
+
+	resigned := false
+	m.AddLeadership("lease", func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		if m.Initiated() {
+			t.Error("C closed before resignation completed")
+		}
+		resigned = true
+		return nil
+	})
+	m.AddLeadership("failing", func(ctx context.Context) error {
+		return errors.New("etcd unavailable") // Logged, doesn't block the broadcast.
+	})
+
+	m.InitiateManual()
+	<-m.C
+	if !resigned {
+		t.Error("C closed before resignation completed")
+	}
+	m.Wait()
+}
+
+func TestLeadershipTimeout(t *testing.T) {
+	m := newTestManager(WithLeadershipTimeout(30 * time.Millisecond))
+
+	ctxDone, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	m.AddLeadership("stuck", func(ctx context.Context) error {
+		<-ctx.Done()
+		close(ctxDone)
+		<-release // Ignores the cancellation.
+		return nil
+	})
+
+	start := time.Now()
+	m.InitiateManual()
+	select {
+	case <-m.C:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stuck resignation blocked the broadcast")
+	}
+	if d := time.Since(start); d < 30*time.Millisecond {
+		t.Errorf("broadcast after %v, want it to wait for the leadership timeout", d)
+	}
+	<-ctxDone // The ctx of resign is cancelled on timeout.
+	m.Wait()
+}
@@ -0,0 +1,70 @@
+package shutdown
+
+import "context"
+
+// Mailbox is a worker's mailbox where control messages (e.g. stop, flush)
+// bypass its data queue. The shutdown signal is also delivered with priority,
+// so it reaches busy workers without waiting behind queued data messages.
+//
+// Use NewMailbox to create one.
+type Mailbox[T any] struct {
+	manager *Manager
+	control chan T
+	data    chan T
+}
+
+// NewMailbox creates a new Mailbox whose data queue has the given capacity.
+func NewMailbox[T any](size int) *Mailbox[T] {
+	return NewMailboxOn[T](std, size)
+}
+
+// NewMailboxOn is like NewMailbox, but the mailbox delivers the shutdown
+// managed by m.
+func NewMailboxOn[T any](m *Manager, size int) *Mailbox[T] {
+	return &Mailbox[T]{
+		manager: m,
+		control: make(chan T, 1),
+		data:    make(chan T, size),
+	}
+}
+
+// Send queues a data message. It blocks if the data queue is full.
+// If shutdown is initiated while blocking (or before), ErrInitiated
+// is returned and msg is not queued.
+func (mb *Mailbox[T]) Send(msg T) error {
+	return SendOn(context.Background(), mb.manager, mb.data, msg)
+}
+
+// SendControl sends a control message, which bypasses the data queue.
+// It blocks until the previous control message is received.
+func (mb *Mailbox[T]) SendControl(msg T) {
+	mb.control <- msg
+}
+
+// Receive returns the next message. control tells if it is a control message.
+//
+// Pending control messages are returned first. Then if shutdown has been
+// initiated, ErrInitiated is returned, else Receive waits for the next message
+// (control or data) or for the shutdown.
+// Once shutdown has been initiated, Receive keeps returning ErrInitiated
+// (after pending control messages): data messages still queued are not
+// delivered anymore.
+func (mb *Mailbox[T]) Receive() (msg T, control bool, err error) {
+	select {
+	case msg = <-mb.control:
+		return msg, true, nil
+	default:
+	}
+	if mb.manager.Initiated() {
+		return msg, false, ErrInitiated
+	}
+
+	select {
+	case msg = <-mb.control:
+		return msg, true, nil
+	case <-mb.manager.C:
+		return msg, false, ErrInitiated
+	case msg = <-mb.data:
+		return msg, false, nil
+	}
+}
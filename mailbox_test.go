@@ -0,0 +1,71 @@
+package shutdown
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMailbox(t *testing.T) {
+	m := newTestManager()
+	mb := NewMailboxOn[string](m, 10)
+
+	mb.Send("data1")
+	mb.Send("data2")
+	mb.SendControl("flush")
+
+	// Control messages bypass the data queue.
+	for _, want := range []struct {
+		msg     string
+		control bool
+	}{{"flush", true}, {"data1", false}, {"data2", false}} {
+		msg, control, err := mb.Receive()
+		if msg != want.msg || control != want.control || err != nil {
+			t.Errorf("Receive returned %q, %t, %v, want %q, %t", msg, control, err, want.msg, want.control)
+		}
+	}
+	m.Close()
+}
+
+func TestMailboxShutdown(t *testing.T) {
+	m := newTestManager()
+	mb := NewMailboxOn[string](m, 1)
+
+	// Shutdown reaches a blocked receiver.
+	time.AfterFunc(20*time.Millisecond, m.InitiateManual)
+	if _, _, err := mb.Receive(); !errors.Is(err, ErrInitiated) {
+		t.Errorf("Receive returned %v, want %v", err, ErrInitiated)
+	}
+
+	// After initiation, pending control messages are still returned,
+	// then ErrInitiated, repeatedly.
+	mb.SendControl("stop")
+	if msg, control, err := mb.Receive(); msg != "stop" || !control || err != nil {
+		t.Errorf("Receive returned %q, %t, %v, want the control message", msg, control, err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, err := mb.Receive(); !errors.Is(err, ErrInitiated) {
+			t.Errorf("Receive returned %v, want %v", err, ErrInitiated)
+		}
+	}
+
+	// Data messages are refused.
+	if err := mb.Send("data"); !errors.Is(err, ErrInitiated) {
+		t.Errorf("Send returned %v, want %v", err, ErrInitiated)
+	}
+	m.Wait()
+}
+
+func TestMailboxShutdownPriority(t *testing.T) {
+	m := newTestManager()
+	mb := NewMailboxOn[string](m, 10)
+	mb.Send("data")
+	m.InitiateManual()
+	<-m.C
+
+	// The shutdown is delivered before queued data messages.
+	if msg, _, err := mb.Receive(); !errors.Is(err, ErrInitiated) {
+		t.Errorf("Receive returned %q, %v, want %v", msg, err, ErrInitiated)
+	}
+	m.Wait()
+}
@@ -0,0 +1,69 @@
+package shutdown
+
+// MainLoop runs functions submitted by RunOnMain, until shutdown is initiated
+// and Wg is done (all registered goroutines are finished).
+//
+// It is for platforms and libraries (e.g. macOS GUI, some CGO SDKs) that
+// require teardown on the main thread. Call it from main(), and to make sure
+// the main goroutine runs on the main thread, lock it in an init function:
+//
+//	func init() {
+//		runtime.LockOSThread()
+//	}
+//
+//	func main() {
+//		// Start your app...
+//
+//		shutdown.MainLoop()
+//	}
+//
+// MainLoop must be called only once.
+func MainLoop() { std.MainLoop() }
+
+// MainLoop runs functions submitted by RunOnMain, until shutdown is initiated
+// and Wg is done. See the package-level MainLoop.
+func (m *Manager) MainLoop() {
+	defer close(m.mainLoopDone)
+
+	c := m.C
+	var wgDone chan struct{} // nil until shutdown is initiated
+	for {
+		select {
+		case f := <-m.mainCh:
+			f()
+		case <-c:
+			c = nil // Don't select this case anymore
+			ch := make(chan struct{})
+			go func() {
+				m.Wg.Wait()
+				close(ch)
+			}()
+			wgDone = ch
+		case <-wgDone:
+			return
+		}
+	}
+}
+
+// RunOnMain runs f on the main goroutine by MainLoop, and waits for it
+// to complete. It returns true if f was run, false if MainLoop has returned
+// (in which case f is not run).
+//
+// If MainLoop is not yet running, RunOnMain blocks until it is.
+func RunOnMain(f func()) bool { return std.RunOnMain(f) }
+
+// RunOnMain runs f on the main goroutine by MainLoop, and waits for it
+// to complete. See the package-level RunOnMain.
+func (m *Manager) RunOnMain(f func()) bool {
+	done := make(chan struct{})
+	select {
+	case m.mainCh <- func() {
+		defer close(done)
+		f()
+	}:
+		<-done
+		return true
+	case <-m.mainLoopDone:
+		return false
+	}
+}
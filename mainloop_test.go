@@ -0,0 +1,76 @@
+package shutdown
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// goid returns the id of the current goroutine.
+func goid() int {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	id, _ := strconv.Atoi(string(buf[:bytes.IndexByte(buf, ' ')]))
+	return id
+}
+
+func TestMainLoop(t *testing.T) {
+	m := newTestManager()
+	mainID := goid()
+
+	var ids []int
+	record := func() { ids = append(ids, goid()) }
+
+	// RunOnMain blocks until MainLoop is running.
+	m.Go(func() {
+		if !m.RunOnMain(record) {
+			t.Error("RunOnMain returned false before MainLoop returned")
+		}
+	})
+	// Teardown running on the main goroutine during shutdown.
+	m.OnShutdown(func() {
+		if !m.RunOnMain(record) {
+			t.Error("RunOnMain returned false during shutdown")
+		}
+	})
+	time.AfterFunc(20*time.Millisecond, m.InitiateManual)
+
+	m.MainLoop() // Returns when shutdown is initiated and Wg is done.
+
+	if len(ids) != 2 {
+		t.Fatalf("%d functions run on main, want 2", len(ids))
+	}
+	for _, id := range ids {
+		if id != mainID {
+			t.Errorf("function run on goroutine %d, want %d", id, mainID)
+		}
+	}
+	if m.RunOnMain(func() { t.Error("f run after MainLoop returned") }) {
+		t.Error("RunOnMain returned true after MainLoop returned")
+	}
+	m.Wait()
+}
+
+func TestWithMainThread(t *testing.T) {
+	m := newTestManager()
+	mainID := goid()
+
+	hookID := -1
+	m.OnShutdown(func() { hookID = goid() }, WithMainThread(), WithName("gui"))
+	m.OnShutdownError(func() error { panic("boom") }, WithMainThread(), WithName("panicking"))
+	time.AfterFunc(20*time.Millisecond, m.InitiateManual)
+
+	m.MainLoop()
+	m.Wait()
+
+	if hookID != mainID {
+		t.Errorf("hook run on goroutine %d, want %d", hookID, mainID)
+	}
+	if errs := m.Errors(); len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "shutdown hook panicking:") {
+		t.Errorf("got hook errors %v, want the panic of the panicking hook", errs)
+	}
+}
@@ -0,0 +1,105 @@
+package shutdown
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"time"
+)
+
+// Marker is a clean shutdown marker used to detect unclean shutdowns
+// (crashes, force kills). Use OpenMarker to create one.
+//
+// The marker file is written at startup, and is removed only when shutdown
+// completes successfully (when Clear is called). If the file exists at the next
+// startup, the previous run did not exit cleanly.
+type Marker struct {
+	path string
+
+	// unclean is the number of consecutive unclean exits before the current run.
+	unclean int
+}
+
+// OpenMarker writes the marker file at path, marking the current run as
+// in progress. prevClean tells if the previous run exited cleanly
+// (true if there was no previous run).
+//
+// Call Clear on the returned Marker when shutdown completes successfully,
+// e.g. right before returning from main().
+func OpenMarker(path string) (mk *Marker, prevClean bool, err error) {
+	mk = &Marker{path: path}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		// Previous run did not exit cleanly. If the previous count can't be
+		// parsed, it's treated as 0.
+		fmt.Sscanf(string(data), "unclean: %d", &mk.unclean)
+		mk.unclean++
+	case errors.Is(err, fs.ErrNotExist):
+	default:
+		return nil, false, err
+	}
+
+	data = []byte(fmt.Sprintf("unclean: %d\npid: %d\nstarted: %s\n",
+		mk.unclean, os.Getpid(), appStartedAt.Format(time.RFC3339)))
+	if err = os.WriteFile(path, data, 0o644); err != nil {
+		return nil, false, err
+	}
+
+	return mk, mk.unclean == 0, nil
+}
+
+// Unclean returns the number of consecutive unclean exits before the current run.
+func (mk *Marker) Unclean() int {
+	return mk.unclean
+}
+
+// Backoff delays startup if previous runs exited uncleanly, protecting
+// downstream systems from a crash-looping instance.
+// The delay is base after 1 unclean exit, and doubles with each further
+// consecutive unclean exit, but is capped at maxDelay (if maxDelay > 0).
+//
+// Backoff returns early if shutdown is initiated (see Sleep). It returns
+// false in that case, true otherwise.
+//
+// Backoff uses the default Manager, use BackoffOn to use another one.
+func (mk *Marker) Backoff(base, maxDelay time.Duration) bool {
+	return mk.BackoffOn(std, base, maxDelay)
+}
+
+// BackoffOn delays startup like Backoff, but it logs using m, and returns
+// early if shutdown of m is initiated.
+func (mk *Marker) BackoffOn(m *Manager, base, maxDelay time.Duration) bool {
+	if mk.unclean == 0 {
+		return true
+	}
+
+	d := mk.backoffDelay(base, maxDelay)
+	m.warnf("Previous %d run(s) exited uncleanly, delaying startup by %v...", mk.unclean, d)
+	return m.Sleep(d)
+}
+
+// backoffDelay returns the startup delay, see Backoff. Doubling saturates
+// at the max Duration, so a long crash loop can't overflow it.
+func (mk *Marker) backoffDelay(base, maxDelay time.Duration) time.Duration {
+	d := base
+	for i := 1; i < mk.unclean && (maxDelay <= 0 || d < maxDelay); i++ {
+		if d > math.MaxInt64/2 {
+			d = math.MaxInt64
+			break
+		}
+		d *= 2
+	}
+	if maxDelay > 0 && d > maxDelay {
+		d = maxDelay
+	}
+	return d
+}
+
+// Clear removes the marker file, marking the current run as exited cleanly.
+func (mk *Marker) Clear() error {
+	return os.Remove(mk.path)
+}
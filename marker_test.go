@@ -0,0 +1,73 @@
+package shutdown
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMarker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "marker")
+
+	for i, wantClean := range []bool{true, false, false} {
+		mk, prevClean, err := OpenMarker(path)
+		if err != nil {
+			t.Fatalf("[run %d] OpenMarker failed: %v", i, err)
+		}
+		if prevClean != wantClean || mk.Unclean() != i {
+			t.Errorf("[run %d] prevClean: %t, unclean: %d, want %t, %d", i, prevClean, mk.Unclean(), wantClean, i)
+		}
+	}
+
+	mk, _, _ := OpenMarker(path)
+	if err := mk.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, prevClean, _ := OpenMarker(path); !prevClean {
+		t.Error("previous run not clean after Clear")
+	}
+}
+
+func TestMarkerBackoff(t *testing.T) {
+	m := newTestManager()
+
+	// 3 unclean exits: base doubled twice.
+	start := time.Now()
+	if !(&Marker{unclean: 3}).BackoffOn(m, 10*time.Millisecond, 0) {
+		t.Error("BackoffOn returned false before shutdown")
+	}
+	if d := time.Since(start); d < 40*time.Millisecond {
+		t.Errorf("BackoffOn delayed %v, want at least 40ms", d)
+	}
+
+	// Capped at maxDelay, returns early on shutdown.
+	m.InitiateManual()
+	start = time.Now()
+	if (&Marker{unclean: 10}).BackoffOn(m, time.Second, time.Hour) {
+		t.Error("BackoffOn returned true after shutdown")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("BackoffOn did not return early on shutdown (%v)", d)
+	}
+}
+
+func TestMarkerBackoffDelay(t *testing.T) {
+	cases := []struct {
+		unclean  int
+		maxDelay time.Duration
+		want     time.Duration
+	}{
+		{1, 0, time.Second},
+		{3, 0, 4 * time.Second},
+		{3, 3 * time.Second, 3 * time.Second},
+		{40, time.Hour, time.Hour},
+		{40, 0, math.MaxInt64}, // Saturates instead of overflowing.
+		{1000, 0, math.MaxInt64},
+	}
+	for _, c := range cases {
+		if got := (&Marker{unclean: c.unclean}).backoffDelay(time.Second, c.maxDelay); got != c.want {
+			t.Errorf("backoffDelay with %d unclean exits, max %v = %v, want %v", c.unclean, c.maxDelay, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package shutdown
+
+// Notifier is a read-only view of the shutdown. Application code may hand it
+// to libraries, so they can observe the shutdown without being able to
+// initiate it or to register hooks.
+type Notifier interface {
+	// Done returns a channel that is closed when shutdown is initiated
+	// (the shutdown channel C).
+	Done() <-chan struct{}
+
+	// Initiated tells if a shutdown has been initiated.
+	Initiated() bool
+
+	// Reason returns the cause of the shutdown, see the package-level Reason.
+	Reason() string
+}
+
+// ReadOnly returns a Notifier of the shutdown.
+func ReadOnly() Notifier { return std.ReadOnly() }
+
+// ReadOnly returns a Notifier of the shutdown managed by m.
+func (m *Manager) ReadOnly() Notifier {
+	return notifier{m: m}
+}
+
+// notifier implements Notifier.
+type notifier struct {
+	m *Manager
+}
+
+// Done implements Notifier.
+func (n notifier) Done() <-chan struct{} { return n.m.C }
+
+// Initiated implements Notifier.
+func (n notifier) Initiated() bool { return n.m.Initiated() }
+
+// Reason implements Notifier.
+func (n notifier) Reason() string { return n.m.Reason() }
@@ -0,0 +1,39 @@
+package shutdown
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadOnly(t *testing.T) {
+	m := newTestManager()
+	n := m.ReadOnly()
+
+	// The read-only view can't be used to initiate or register hooks.
+	if _, ok := n.(interface{ InitiateManual() }); ok {
+		t.Error("Notifier can initiate a shutdown")
+	}
+	if _, ok := n.(interface{ OnShutdown(func(), ...HookOption) }); ok {
+		t.Error("Notifier can register hooks")
+	}
+
+	if n.Initiated() || n.Reason() != "" {
+		t.Errorf("Notifier before initiation: initiated: %t, reason: %q", n.Initiated(), n.Reason())
+	}
+	select {
+	case <-n.Done():
+		t.Error("Done closed before initiation")
+	default:
+	}
+
+	m.InitiateManualReason("deploy")
+	select {
+	case <-n.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Done not closed on shutdown")
+	}
+	if !n.Initiated() || n.Reason() != "manual: deploy" {
+		t.Errorf("Notifier after initiation: initiated: %t, reason: %q", n.Initiated(), n.Reason())
+	}
+	m.Wait()
+}
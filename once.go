@@ -0,0 +1,37 @@
+package shutdown
+
+import "sync"
+
+// Once returns a function that calls f exactly once: either when the returned
+// function is called (the first time), or when shutdown is initiated, whichever
+// happens first. Subsequent calls of the returned function are no-ops.
+//
+// This is useful for cleanup (e.g. closing a resource) that might be called from
+// multiple paths.
+//
+// If Once is called after shutdown has been initiated and the final Wait has
+// been called (see Go), f is not called by the shutdown anymore: it is only
+// called when the returned function is called.
+func Once(f func()) func() { return std.Once(f) }
+
+// Once returns a function that calls f exactly once: either when the returned
+// function is called, or when shutdown is initiated. See the package-level Once.
+func (m *Manager) Once(f func()) func() {
+	once := &sync.Once{}
+	calledCh := make(chan struct{})
+
+	m.tryGo(func() {
+		select {
+		case <-calledCh:
+		case <-m.C:
+		}
+		once.Do(f)
+	})
+
+	return func() {
+		once.Do(func() {
+			f()
+			close(calledCh)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package shutdown
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestOnce(t *testing.T) {
+	m := newTestManager()
+	var calls int32
+	f := m.Once(func() { atomic.AddInt32(&calls, 1) })
+
+	f()
+	f()
+	m.Close()
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("f called %d times, want 1", n)
+	}
+}
+
+func TestOnceShutdown(t *testing.T) {
+	m := newTestManager()
+	var calls int32
+	f := m.Once(func() { atomic.AddInt32(&calls, 1) })
+
+	// The final Wait waits for f called on shutdown.
+	m.Close()
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("f called %d times on shutdown, want 1", n)
+	}
+	f()
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("f called %d times, want 1", n)
+	}
+}
+
+func TestOnceAfterWait(t *testing.T) {
+	m := newTestManager()
+	m.Close()
+
+	var calls int32
+	f := m.Once(func() { atomic.AddInt32(&calls, 1) })
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Errorf("f called %d times by Once after the final Wait, want 0", n)
+	}
+	f()
+	f()
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("f called %d times, want 1", n)
+	}
+}
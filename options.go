@@ -0,0 +1,454 @@
+package shutdown
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+)
+
+// Logger is used to log shutdown events. *log.Logger implements it.
+// Loggers also implementing LevelLogger receive the level of the events.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// Level is the level of a logged shutdown event, see LevelLogger.
+type Level int
+
+// Levels of logged shutdown events.
+const (
+	// LevelInfo is for the progress of the shutdown.
+	LevelInfo Level = iota
+
+	// LevelNotice is for the milestones of the shutdown: its initiation
+	// and its completion.
+	LevelNotice
+
+	// LevelWarn is for timeouts, ignored events and skipped steps.
+	LevelWarn
+
+	// LevelError is for panics, failures and forced exits.
+	LevelError
+)
+
+// LevelLogger is a Logger receiving the level of the logged events: if the
+// logger of a manager (see WithLogger) implements it, events are logged with
+// Logf instead of Printf.
+type LevelLogger interface {
+	Logger
+
+	// Logf logs an event of the given level.
+	Logf(level Level, format string, v ...any)
+}
+
+// Option is an option of a Manager.
+type Option func(m *Manager)
+
+// WithLogger sets the logger of the manager. The default is the standard
+// logger of the log package.
+func WithLogger(l Logger) Option {
+	return func(m *Manager) {
+		m.logger = l
+	}
+}
+
+// WithGraceTimeout sets the max time the final Wait waits for goroutines
+// registered in Wg (including the shutdown hooks) after shutdown has been
+// initiated. If the timeout is exceeded, it is logged, and Wait moves on.
+// The default is 0, which means no timeout.
+func WithGraceTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.graceTimeout = timeout
+	}
+}
+
+// WithSignals sets the OS signals that initiate a shutdown. The default Manager
+// handles SIGTERM and SIGINT, Managers created with New handle no signals by
+// default. If no signals are provided, no signals are handled (e.g. when running
+// under a supervisor that initiates shutdown by other means).
+//
+// If any of these signals is received after shutdown has been initiated, the
+// escalation policy is applied (see WithEscalation): by default the app is
+// exited immediately with the force exit code (see WithForceExitCode).
+//
+// Signals not handled keep their default behavior (e.g. SIGHUP terminates the app).
+func WithSignals(sigs ...os.Signal) Option {
+	return func(m *Manager) {
+		m.signals = sigs
+	}
+}
+
+// WithWatchdog enables a watchdog: if goroutines registered in Wg (including
+// the shutdown hooks) don't complete within timeout after shutdown has been
+// initiated, the app is exited with the force exit code (see WithForceExitCode).
+// Unlike WithGraceTimeout, this does not depend on the app calling the final
+// Wait, so e.g. a goroutine that never returns can't keep the process alive.
+// The exit may be delayed by running PhaseCgo hooks (see WithCgoExitTimeout)
+// and the last-gasp function (see SetLastGasp).
+// The default is 0, which means no watchdog.
+func WithWatchdog(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.watchdog = timeout
+	}
+}
+
+// WithHardKill arms a fallback timer when shutdown is initiated: if the final
+// Wait does not return within timeout, the app is exited with the force exit
+// code (see WithForceExitCode). The timer is independent of the rest of the
+// shutdown machinery (unlike WithWatchdog, it does not wait for Wg or the
+// hooks, and it doesn't take internal locks), so it also works as a last line
+// of defense if the hook runner itself gets stuck. For the same reason it does
+// not wait for running PhaseCgo hooks (see OnCgoCleanup), and it is not
+// extended by WithSuspendAwareness; the exit is only delayed by the last-gasp
+// function (no longer than 1 second, see SetLastGasp). It should be larger
+// than the watchdog timeout and the grace timeout, if any.
+// The default is 0, which means no hard kill timer.
+func WithHardKill(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.hardKill = timeout
+	}
+}
+
+// WithFastExit enables the fast path for apps not using shutdown handling
+// (e.g. small CLIs importing shared app scaffolding): if a shutdown signal is
+// received while nothing is registered (no hooks, servers, leaderships, holds,
+// open critical sections, and no goroutines started with Go or by the helpers
+// of the package), the app is exited immediately with the conventional exit
+// code 128+signal number, instead of broadcasting a shutdown nobody watches.
+//
+// Goroutines registered by calling Wg.Add directly can't be detected:
+// apps using them should not enable fast exit.
+func WithFastExit() Option {
+	return func(m *Manager) {
+		m.fastExit = true
+	}
+}
+
+// WithConfirmWindow sets a confirm window for shutdown signals: when a signal
+// is received, shutdown is initiated only after window, during which the pending
+// shutdown can be aborted by calling Abort. Useful for interactive tools, where
+// an accidental CTRL+C shouldn't immediately start tearing everything down.
+// Receiving the signal again during the window initiates shutdown immediately.
+// The default is 0, which means no confirm window.
+func WithConfirmWindow(window time.Duration) Option {
+	return func(m *Manager) {
+		m.confirmWindow = window
+	}
+}
+
+// WithSignalDebounce sets a debounce window for shutdown signals: a signal
+// identical to the previous one, received within window after it, is ignored
+// silently (it is not escalated, see WithEscalation). Useful when orchestrators
+// deliver the same signal repeatedly. The default is 0, which means no debounce.
+func WithSignalDebounce(window time.Duration) Option {
+	return func(m *Manager) {
+		m.debounce = window
+	}
+}
+
+// WithForceExitCode sets the exit code used when the app is exited forcefully:
+// by escalation (see EscalateExit), the watchdog (see WithWatchdog) or the hard
+// kill timer (see WithHardKill). The default is 2.
+func WithForceExitCode(code int) Option {
+	return func(m *Manager) {
+		m.forceExitCode = code
+	}
+}
+
+// Escalation is an action taken when a shutdown signal is received after
+// shutdown has been initiated.
+type Escalation int
+
+// Escalation actions.
+const (
+	// EscalateIgnore logs and ignores the signal.
+	EscalateIgnore Escalation = iota
+
+	// EscalateCritical skips remaining waits: hooks not marked critical (see
+	// WithCritical) are skipped or abandoned, the final Wait only waits for
+	// critical hooks, and servers registered with ManageMetrics don't wait for
+	// a final scrape.
+	EscalateCritical
+
+	// EscalateExit exits the app immediately with the force exit code
+	// (see WithForceExitCode).
+	EscalateExit
+)
+
+// WithEscalation sets the escalation policy: the actions taken when shutdown
+// signals (see WithSignals) are received after shutdown has been initiated.
+// actions[0] is taken on the first such signal (e.g. the second CTRL+C),
+// actions[1] on the next one and so on, the last action is repeated.
+//
+// The default is EscalateExit. For example, to skip remaining waits on
+// the second signal and exit on the third one:
+//
+//	shutdown.Init(shutdown.WithEscalation(shutdown.EscalateCritical, shutdown.EscalateExit))
+func WithEscalation(actions ...Escalation) Option {
+	return func(m *Manager) {
+		m.escalation = actions
+	}
+}
+
+// WithAutoExit makes the manager exit the app when shutdown completes:
+// when shutdown is initiated, Exit is called automatically (which performs
+// the final Wait and exits with the exit code of the shutdown).
+//
+// When auto exit is enabled, the app doesn't have to call Wait itself
+// (but it may do so).
+func WithAutoExit() Option {
+	return func(m *Manager) {
+		m.autoExit = true
+	}
+}
+
+// Init configures the default Manager with the given options.
+// Init should be called at the start of main(), before using other
+// functions of the package.
+func Init(opts ...Option) {
+	std.configure(opts)
+}
+
+// configure applies opts to m.
+func (m *Manager) configure(opts []Option) {
+	autoExit, signals := m.autoExit, m.signals
+	reloadConfigured, reloadSignals := m.reloadConfigured, m.reloadSignals
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.sigSrc == nil || !equalSignals(m.signals, signals) {
+		m.subscribeSignals()
+	}
+	if m.reloadConfigured && (!reloadConfigured || !equalSignals(m.reloadSignals, reloadSignals)) {
+		m.subscribeReload()
+	}
+	if m.quitDiagnostics {
+		m.subscribeQuit()
+	}
+	m.updateStatusFile()
+
+	if m.autoExit && !autoExit {
+		go func() {
+			<-m.C
+			m.Exit()
+		}()
+	}
+}
+
+// subscribeSignals subscribes to m.signals, replacing the previous subscription.
+func (m *Manager) subscribeSignals() {
+	old := m.sigSrc
+	m.sigSrc = nil
+	if len(m.signals) > 0 {
+		// Subscribe to the new signals before unsubscribing from the old ones,
+		// so there is no window in which signals get their default behavior.
+		m.sigSrc = newSignalSource(m, m.signals...)
+		m.sigSrc.confirm = m.confirm
+		m.sigSrc.onSignal = m.onSignal
+		m.sigSrc.force = m.escalate
+		m.AddSource(m.sigSrc)
+	}
+	if old != nil {
+		old.unsubscribe()
+	}
+}
+
+// runWatchdog exits the app if Wg does not complete within the watchdog timeout
+// (see WithWatchdog). It must be called after the hook runner is registered in Wg.
+func (m *Manager) runWatchdog() {
+	timeout := m.watchdog
+	if timeout <= 0 {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.Wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-m.deadline(timeout):
+		m.errorf("Shutdown did not complete within %v (watchdog), forcing exit...", timeout)
+		m.exit(m.forceExitCode, "watchdog")
+	}
+}
+
+// hardKillExit exits the app because the final Wait did not return within the
+// hard kill timeout (see WithHardKill). Unlike exit, it doesn't take m.mu and
+// doesn't wait for PhaseCgo, so it works even if the package itself is stuck.
+func (m *Manager) hardKillExit(timeout time.Duration) {
+	m.errorf("Shutdown did not complete within %v (hard kill), forcing exit...", timeout)
+	m.callLastGasp("hard kill")
+	os.Exit(m.forceExitCode)
+}
+
+// confirm waits for the confirm window after sig has been received, and tells
+// if shutdown is to be initiated (false if it is aborted).
+// ctx is done when shutdown is initiated by other means, more delivers
+// subsequent signals.
+func (m *Manager) confirm(ctx context.Context, sig os.Signal, more <-chan os.Signal) bool {
+	window := m.confirmWindow
+	if window <= 0 {
+		return true
+	}
+
+	abort := make(chan struct{})
+	m.mu.Lock()
+	m.abortCh = abort
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.abortCh = nil
+		m.mu.Unlock()
+	}()
+
+	m.noticef("Received '%v' signal, shutting down in %v (call Abort to cancel)...", sig, window)
+	t := time.NewTimer(window)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	case sig = <-more:
+		m.logf("Received '%v' signal again, not waiting for the confirm window.", sig)
+	case <-abort:
+		m.warnf("Shutdown aborted.")
+		return false
+	}
+	return true
+}
+
+// onSignal is called with the signal initiating shutdown.
+func (m *Manager) onSignal(sig os.Signal) {
+	m.debounced(sig)
+	m.fastExitIfIdle(sig)
+}
+
+// debounced records sig as the last signal, and tells if it is to be ignored
+// because it is identical to the previous one received within the debounce window.
+func (m *Manager) debounced(sig os.Signal) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	ignore := sig == m.lastSig && now.Sub(m.lastSigAt) < m.debounce
+	m.lastSig, m.lastSigAt = sig, now
+	return ignore
+}
+
+// fastExitIfIdle exits the app if fast exit is enabled and nothing is
+// registered (see WithFastExit). sig is the received signal.
+func (m *Manager) fastExitIfIdle(sig os.Signal) {
+	m.mu.Lock()
+	enabled := m.fastExit
+	idle := len(m.hooks) == 0 && len(m.deferred) == 0 && len(m.leaderships) == 0 && len(m.lastServers) == 0 &&
+		m.holds == 0 && m.guards == 0 && m.tasks == 0
+	for _, hs := range m.phaseHooks {
+		idle = idle && len(hs) == 0
+	}
+	m.mu.Unlock()
+
+	if !enabled || !idle {
+		return
+	}
+
+	m.noticef("Received '%v' signal, nothing to shut down, exiting...", sig)
+	m.exit(signalExitCode(sig), "fast exit")
+}
+
+// escalate takes the escalation action, because sig has been received
+// after shutdown has been initiated.
+func (m *Manager) escalate(sig os.Signal) {
+	if m.debounced(sig) {
+		return
+	}
+
+	m.mu.Lock()
+	m.duringSignals++
+	action := EscalateIgnore
+	if n := len(m.escalation); n > 0 {
+		i := m.duringSignals - 1
+		if i >= n {
+			i = n - 1
+		}
+		action = m.escalation[i]
+	}
+	m.mu.Unlock()
+
+	switch action {
+	case EscalateIgnore:
+		m.warnf("Received '%v' signal during shutdown, ignoring.", sig)
+	case EscalateCritical:
+		m.warnf("Received '%v' signal during shutdown, running only critical hooks...", sig)
+		m.escalateCritical()
+	case EscalateExit:
+		m.errorf("Received '%v' signal during shutdown, forcing exit...", sig)
+		m.exit(m.forceExitCode, "escalation")
+	}
+}
+
+// escalateCritical makes shutdown skip remaining waits.
+func (m *Manager) escalateCritical() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.hurried {
+		m.hurried = true
+		close(m.hurry)
+	}
+}
+
+// equalSignals tells if a and b contain the same signals in the same order.
+func equalSignals(a, b []os.Signal) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// logf logs an info event using the logger of m.
+func (m *Manager) logf(format string, v ...any) {
+	m.log(LevelInfo, format, v...)
+}
+
+// noticef logs a milestone using the logger of m.
+func (m *Manager) noticef(format string, v ...any) {
+	m.log(LevelNotice, format, v...)
+}
+
+// warnf logs a warning using the logger of m.
+func (m *Manager) warnf(format string, v ...any) {
+	m.log(LevelWarn, format, v...)
+}
+
+// errorf logs an error using the logger of m.
+func (m *Manager) errorf(format string, v ...any) {
+	m.log(LevelError, format, v...)
+}
+
+// log logs an event of the given level using the logger of m.
+func (m *Manager) log(level Level, format string, v ...any) {
+	if l, ok := m.logger.(LevelLogger); ok {
+		l.Logf(level, format, v...)
+		return
+	}
+	m.logger.Printf(format, v...)
+}
+
+// defaultLogger is the default Logger, using the standard logger of the log
+// package (so it respects log.SetOutput and log.SetFlags).
+type defaultLogger struct{}
+
+// Printf implements Logger.
+func (defaultLogger) Printf(format string, v ...any) {
+	log.Printf(format, v...)
+}
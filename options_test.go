@@ -0,0 +1,266 @@
+package shutdown
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// waitPendingAbort waits until a shutdown signal is pending in the confirm window
+// of m, and aborts it. It exits the app with code 4 if nothing is pending
+// within 5 seconds.
+func waitPendingAbort(m *Manager) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !m.Abort() {
+		if time.Now().After(deadline) {
+			os.Exit(4)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestConfirmWindowAbort(t *testing.T) {
+	if inChild(t) {
+		m := New(WithSignals(syscall.SIGTERM), WithConfirmWindow(300*time.Millisecond))
+		raise(syscall.SIGTERM)
+		waitPendingAbort(m)
+		time.Sleep(500 * time.Millisecond)
+		if m.Initiated() {
+			os.Exit(3)
+		}
+
+		// After an abort, signals are handled again.
+		raise(syscall.SIGTERM)
+		<-m.C
+		m.Wait()
+		os.Exit(m.ExitCode())
+	}
+
+	code, out := runChild(t)
+	if code != 143 {
+		t.Errorf("child exited with %d, want 143, output:\n%s", code, out)
+	}
+	if !strings.Contains(out, "Shutdown aborted.") {
+		t.Errorf("abort not logged, output:\n%s", out)
+	}
+}
+
+func TestConfirmWindowSecondSignal(t *testing.T) {
+	if inChild(t) {
+		m := New(WithSignals(syscall.SIGTERM), WithConfirmWindow(10*time.Second))
+		raise(syscall.SIGTERM)
+		time.Sleep(100 * time.Millisecond)
+		if m.Initiated() {
+			os.Exit(3)
+		}
+
+		// Receiving the signal again skips the rest of the window.
+		raise(syscall.SIGTERM)
+		select {
+		case <-m.C:
+		case <-time.After(5 * time.Second):
+			os.Exit(4)
+		}
+		m.Wait()
+		os.Exit(m.ExitCode())
+	}
+
+	code, out := runChild(t)
+	if code != 143 {
+		t.Errorf("child exited with %d, want 143, output:\n%s", code, out)
+	}
+}
+
+// blockingHook returns a hook blocking for d.
+func blockingHook(d time.Duration) func() {
+	return func() { time.Sleep(d) }
+}
+
+func TestSignalDebounce(t *testing.T) {
+	if inChild(t) {
+		m := New(WithSignals(syscall.SIGTERM), WithSignalDebounce(2*time.Second))
+		m.OnShutdown(blockingHook(500 * time.Millisecond))
+		raise(syscall.SIGTERM)
+		<-m.C
+
+		// Identical signal within the window: not escalated (no forced exit).
+		time.Sleep(50 * time.Millisecond)
+		raise(syscall.SIGTERM)
+		m.Wait()
+		os.Exit(m.ExitCode())
+	}
+
+	code, out := runChild(t)
+	if code != 143 {
+		t.Errorf("child exited with %d, want 143, output:\n%s", code, out)
+	}
+}
+
+func TestSignalDebounceExpired(t *testing.T) {
+	if inChild(t) {
+		m := New(WithSignals(syscall.SIGTERM), WithSignalDebounce(100*time.Millisecond))
+		m.OnShutdown(blockingHook(5 * time.Second))
+		raise(syscall.SIGTERM)
+		<-m.C
+
+		// Identical signal after the window: escalated (forced exit).
+		time.Sleep(300 * time.Millisecond)
+		raise(syscall.SIGTERM)
+		m.Wait()
+		os.Exit(0)
+	}
+
+	code, out := runChild(t)
+	if code != 2 {
+		t.Errorf("child exited with %d, want 2, output:\n%s", code, out)
+	}
+}
+
+func TestEscalation(t *testing.T) {
+	if inChild(t) {
+		m := New(WithSignals(syscall.SIGTERM),
+			WithEscalation(EscalateIgnore, EscalateCritical, EscalateExit))
+		m.OnShutdown(func() { select {} }, WithName("stuck"))
+		criticalRan := false
+		m.OnShutdown(func() { criticalRan = true }, WithName("critical"), WithCritical())
+
+		waitDone := make(chan struct{})
+		go func() {
+			m.Wait()
+			close(waitDone)
+		}()
+
+		raise(syscall.SIGTERM)
+		<-m.C
+		time.Sleep(100 * time.Millisecond)
+
+		// First signal during shutdown: ignored.
+		raise(syscall.SIGTERM)
+		time.Sleep(200 * time.Millisecond)
+		select {
+		case <-waitDone:
+			os.Exit(3)
+		default:
+		}
+
+		// Second one: the stuck hook is abandoned, the critical one is run.
+		raise(syscall.SIGTERM)
+		select {
+		case <-waitDone:
+		case <-time.After(5 * time.Second):
+			os.Exit(4)
+		}
+		if !criticalRan {
+			os.Exit(5)
+		}
+
+		// Third one: forced exit.
+		raise(syscall.SIGTERM)
+		time.Sleep(5 * time.Second)
+		os.Exit(0)
+	}
+
+	code, out := runChild(t)
+	if code != 2 {
+		t.Errorf("child exited with %d, want 2, output:\n%s", code, out)
+	}
+	for _, s := range []string{"during shutdown, ignoring", "running only critical hooks", "forcing exit"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("%q not logged, output:\n%s", s, out)
+		}
+	}
+}
+
+func TestEscalationForceExitCode(t *testing.T) {
+	if inChild(t) {
+		m := New(WithSignals(syscall.SIGTERM), WithForceExitCode(7))
+		m.OnShutdown(func() { select {} })
+		raise(syscall.SIGTERM)
+		<-m.C
+		time.Sleep(100 * time.Millisecond)
+		raise(syscall.SIGTERM)
+		m.Wait()
+		os.Exit(0)
+	}
+
+	code, out := runChild(t)
+	if code != 7 {
+		t.Errorf("child exited with %d, want 7, output:\n%s", code, out)
+	}
+}
+
+func TestFastExit(t *testing.T) {
+	if inChild(t) {
+		New(WithSignals(syscall.SIGTERM), WithFastExit())
+		raise(syscall.SIGTERM)
+		time.Sleep(5 * time.Second)
+		os.Exit(0)
+	}
+
+	code, out := runChild(t)
+	if code != 143 {
+		t.Errorf("child exited with %d, want 143, output:\n%s", code, out)
+	}
+	if !strings.Contains(out, "nothing to shut down") {
+		t.Errorf("fast exit not logged, output:\n%s", out)
+	}
+}
+
+func TestFastExitNotIdle(t *testing.T) {
+	if inChild(t) {
+		m := New(WithSignals(syscall.SIGTERM), WithFastExit())
+		hookRan := false
+		m.OnShutdown(func() { hookRan = true })
+		raise(syscall.SIGTERM)
+		<-m.C
+		m.Wait()
+		if !hookRan {
+			os.Exit(3)
+		}
+		os.Exit(0)
+	}
+
+	code, out := runChild(t)
+	if code != 0 {
+		t.Errorf("child exited with %d, want 0, output:\n%s", code, out)
+	}
+}
+
+func TestHardKill(t *testing.T) {
+	if inChild(t) {
+		m := New(WithSignals(syscall.SIGTERM), WithHardKill(200*time.Millisecond))
+		m.OnShutdown(func() { select {} })
+		raise(syscall.SIGTERM)
+		<-m.C
+		m.Wait()
+		os.Exit(0)
+	}
+
+	start := time.Now()
+	code, out := runChild(t)
+	if code != 2 {
+		t.Errorf("child exited with %d, want 2, output:\n%s", code, out)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("hard kill took %v", d)
+	}
+}
+
+func TestHardKillDisarmed(t *testing.T) {
+	if inChild(t) {
+		m := New(WithSignals(syscall.SIGTERM), WithHardKill(300*time.Millisecond))
+		raise(syscall.SIGTERM)
+		<-m.C
+		m.Wait()
+		// The timer is stopped when the final Wait returns in time.
+		time.Sleep(600 * time.Millisecond)
+		os.Exit(0)
+	}
+
+	code, out := runChild(t)
+	if code != 0 {
+		t.Errorf("child exited with %d, want 0, output:\n%s", code, out)
+	}
+}
@@ -0,0 +1,134 @@
+package shutdown
+
+import "sync"
+
+// Stage is a stage of a Pipeline.
+type Stage[T any] struct {
+	// Workers is the number of concurrent workers of the stage.
+	// Values less than 1 are treated as 1.
+	Workers int
+
+	// Do processes a value. It returns the output value, and whether the output
+	// is to be passed to the next stage. Outputs of the last stage are dropped.
+	Do func(v T) (out T, ok bool)
+}
+
+// Pipeline is a multi-stage pipeline whose stages are connected with channels.
+// It manages the stage channels so that it tears down without panics or
+// deadlocks: when shutdown is initiated (or Close is called), the intake is
+// closed, and closure propagates through the stages in order, each stage
+// draining its input before closing its output.
+//
+// Use NewPipeline to create one.
+type Pipeline[T any] struct {
+	m  *Manager
+	in chan T
+
+	mu     sync.RWMutex // Protects closed, and sends on in
+	closed bool
+
+	closeOnce sync.Once
+	done      chan struct{} // Closed when all stages are finished
+}
+
+// NewPipeline creates and starts a new Pipeline with the given stages.
+// size is the buffer size of the channels between stages.
+func NewPipeline[T any](size int, stages ...Stage[T]) *Pipeline[T] {
+	return NewPipelineOn(std, size, stages...)
+}
+
+// NewPipelineOn is like NewPipeline, but the pipeline is closed when
+// the shutdown managed by m is initiated, and it is registered in the Wg of m.
+func NewPipelineOn[T any](m *Manager, size int, stages ...Stage[T]) *Pipeline[T] {
+	p := &Pipeline[T]{
+		m:    m,
+		in:   make(chan T, size),
+		done: make(chan struct{}),
+	}
+
+	var in <-chan T = p.in
+	for _, stage := range stages {
+		out := make(chan T, size)
+		startStage(stage, in, out)
+		in = out
+	}
+
+	go func() {
+		select {
+		case <-m.C:
+			p.Close()
+		case <-p.done:
+		}
+	}()
+
+	m.Go(func() {
+		defer close(p.done)
+
+		// Drop outputs of the last stage, until it is finished:
+		for range in {
+		}
+	})
+
+	return p
+}
+
+// startStage starts the workers of stage, reading from in, and writing to out.
+// out is closed when all workers are finished.
+func startStage[T any](stage Stage[T], in <-chan T, out chan<- T) {
+	n := stage.Workers
+	if n < 1 {
+		n = 1
+	}
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v := range in {
+				if v, ok := stage.Do(v); ok {
+					out <- v
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+}
+
+// Feed feeds v into the pipeline. It blocks if the intake is full.
+// If the intake is closed (shutdown is initiated or Close is called),
+// ErrInitiated is returned and v is not fed.
+func (p *Pipeline[T]) Feed(v T) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrInitiated
+	}
+	select {
+	case p.in <- v:
+		return nil
+	case <-p.m.C:
+		return ErrInitiated
+	}
+}
+
+// Close closes the intake of the pipeline (e.g. when there is no more input),
+// and the pipeline drains. It may be called multiple times.
+func (p *Pipeline[T]) Close() {
+	p.closeOnce.Do(func() {
+		p.mu.Lock()
+		p.closed = true
+		close(p.in)
+		p.mu.Unlock()
+	})
+}
+
+// Done returns a channel that is closed when all stages are finished.
+func (p *Pipeline[T]) Done() <-chan struct{} {
+	return p.done
+}
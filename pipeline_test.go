@@ -0,0 +1,87 @@
+package shutdown
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPipeline(t *testing.T) {
+	m := newTestManager()
+
+	var mu sync.Mutex
+	var got []int
+	p := NewPipelineOn(m, 2,
+		Stage[int]{Workers: 3, Do: func(v int) (int, bool) {
+			time.Sleep(time.Millisecond)
+			return v * 10, v%2 == 0 // Odd values are dropped.
+		}},
+		Stage[int]{Do: func(v int) (int, bool) {
+			mu.Lock()
+			got = append(got, v)
+			mu.Unlock()
+			return v, true
+		}},
+	)
+
+	for i := 0; i < 10; i++ {
+		if err := p.Feed(i); err != nil {
+			t.Fatalf("Feed returned %v", err)
+		}
+	}
+
+	// Values fed before shutdown are drained through all stages,
+	// and the final Wait waits for the drain.
+	m.Close()
+	select {
+	case <-p.Done():
+	default:
+		t.Error("final Wait returned before the pipeline was drained")
+	}
+
+	mu.Lock()
+	sort.Ints(got)
+	mu.Unlock()
+	if want := []int{0, 20, 40, 60, 80}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if err := p.Feed(1); !errors.Is(err, ErrInitiated) {
+		t.Errorf("Feed after shutdown returned %v, want %v", err, ErrInitiated)
+	}
+}
+
+func TestPipelineClose(t *testing.T) {
+	m := newTestManager()
+	n := 0
+	p := NewPipelineOn(m, 0, Stage[int]{Do: func(v int) (int, bool) { n++; return v, true }})
+	p.Feed(1)
+	p.Close()
+	p.Close()
+	<-p.Done()
+	if n != 1 {
+		t.Errorf("%d values processed, want 1", n)
+	}
+	if err := p.Feed(2); !errors.Is(err, ErrInitiated) {
+		t.Errorf("Feed after Close returned %v, want %v", err, ErrInitiated)
+	}
+	m.Close()
+}
+
+func TestPipelineFeedBlockedShutdown(t *testing.T) {
+	m := newTestManager()
+	release := make(chan struct{})
+	p := NewPipelineOn(m, 0, Stage[int]{Do: func(v int) (int, bool) { <-release; return v, true }})
+	p.Feed(1) // Blocks the stage.
+
+	time.AfterFunc(20*time.Millisecond, m.InitiateManual)
+	// Feed blocked on the full intake is aborted by shutdown.
+	if err := p.Feed(2); !errors.Is(err, ErrInitiated) {
+		t.Errorf("blocked Feed returned %v, want %v", err, ErrInitiated)
+	}
+	close(release)
+	m.Wait()
+}
@@ -0,0 +1,88 @@
+package shutdown
+
+import (
+	"os"
+	"time"
+)
+
+// Environment is an environment with a preset configuration, see Profile.
+type Environment int
+
+// Environments.
+const (
+	// Dev is for local development: fast, chatty, short-grace shutdowns, so
+	// e.g. CTRL+C of a "go run" feels instant:
+	//   - shutdown events are printed to stderr using NewConsoleLogger,
+	//   - a second signal exits immediately (see EscalateExit),
+	//   - the grace timeout is 2 seconds, holds, leadership resignations and
+	//     servers are waited for no longer than 1 second,
+	//   - blocking callbacks are reported after 200 milliseconds.
+	//
+	// Fast exit (see WithFastExit) is not enabled, as it can't detect
+	// goroutines registered by calling Wg.Add directly: add it explicitly if
+	// the app doesn't use them.
+	Dev Environment = iota
+
+	// Prod is for production: full drains, quiet logs and strict budgets:
+	//   - only the initiation and the completion of the shutdown, warnings
+	//     and errors are logged (using the standard logger, see LevelNotice),
+	//   - a second signal runs only critical hooks (see EscalateCritical),
+	//     a third one exits,
+	//   - the grace timeout is 30 seconds, the watchdog fires after 40
+	//     seconds, the hard kill timer after 50 seconds (see WithGraceTimeout,
+	//     WithWatchdog and WithHardKill).
+	Prod
+)
+
+// Profile returns an Option applying the preset configuration of env.
+// The preset may be overridden by options following it, e.g.:
+//
+//	shutdown.Init(shutdown.Profile(shutdown.Dev), shutdown.WithGraceTimeout(5*time.Second))
+//
+// Profile panics if env is not one of the defined environments.
+func Profile(env Environment) Option {
+	var opts []Option
+	switch env {
+	case Dev:
+		opts = []Option{
+			WithLogger(NewConsoleLogger(os.Stderr, false)),
+			WithEscalation(EscalateExit),
+			WithGraceTimeout(2 * time.Second),
+			WithHoldTimeout(time.Second),
+			WithLeadershipTimeout(time.Second),
+			WithServerShutdownTimeout(time.Second),
+			WithBlockingThreshold(200 * time.Millisecond),
+		}
+	case Prod:
+		opts = []Option{
+			WithLogger(quietLogger{defaultLogger{}}),
+			WithEscalation(EscalateCritical, EscalateExit),
+			WithGraceTimeout(30 * time.Second),
+			WithWatchdog(40 * time.Second),
+			WithHardKill(50 * time.Second),
+		}
+	default:
+		panic("shutdown: unknown environment")
+	}
+
+	return func(m *Manager) {
+		for _, opt := range opts {
+			opt(m)
+		}
+	}
+}
+
+// quietLogger is a Logger only forwarding milestones, warnings and errors to l.
+type quietLogger struct {
+	l Logger
+}
+
+// Printf implements Logger. Info lines are dropped.
+func (q quietLogger) Printf(format string, v ...any) {}
+
+// Logf implements LevelLogger.
+func (q quietLogger) Logf(level Level, format string, v ...any) {
+	if level >= LevelNotice {
+		q.l.Printf(format, v...)
+	}
+}
@@ -0,0 +1,56 @@
+package shutdown
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// logRecorder is a Logger recording the logged formats.
+type logRecorder struct {
+	recorder
+}
+
+func (l *logRecorder) Printf(format string, v ...any) { l.add(format) }
+
+func TestProfile(t *testing.T) {
+	m := New(Profile(Dev), WithGraceTimeout(5*time.Second))
+	if m.graceTimeout != 5*time.Second || m.holdTimeout != time.Second {
+		t.Errorf("dev profile not applied or not overridden: grace: %v, hold: %v", m.graceTimeout, m.holdTimeout)
+	}
+	// Fast exit would break apps registering goroutines with Wg.Add.
+	if m.fastExit {
+		t.Error("dev profile enables fast exit")
+	}
+
+	m = New(Profile(Prod))
+	if err := m.Validate(); err != nil {
+		t.Errorf("prod profile is invalid: %v", err)
+	}
+	if _, ok := m.logger.(quietLogger); !ok {
+		t.Errorf("prod logger is %T, want quietLogger", m.logger)
+	}
+}
+
+func TestQuietLogger(t *testing.T) {
+	l := &logRecorder{}
+	q := quietLogger{l}
+	q.Printf("Waiting for %d hold(s) before broadcasting shutdown...", 1)
+	q.Logf(LevelInfo, "Stopping %s (system shutdown)...", "server")
+	q.Logf(LevelNotice, "Received '%v' signal, broadcasting shutdown...", "terminated")
+	q.Logf(LevelWarn, "Shutdown hook %v timed out after %v, moving on.", "h", time.Second)
+	q.Logf(LevelError, "Shutdown hook %v panicked: %v\n%s", "h", "boom", "stack")
+
+	if len(l.events) != 3 || !strings.HasPrefix(l.events[0], "Received") {
+		t.Errorf("logged %q, want only the initiation, the warning and the error", l.events)
+	}
+}
+
+func TestProfileUnknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Profile did not panic on unknown environment")
+		}
+	}()
+	Profile(Environment(42))
+}
@@ -0,0 +1,48 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// TrackRedisClient registers a Redis-like client to be flushed and closed in
+// PhaseStop: after traffic has been drained (PhaseDrain), but before the final
+// cleanup (PhaseCleanup, e.g. flushing logs). name is used in logs and errors.
+//
+// The flush functions (e.g. executing outstanding pipelines, unsubscribing
+// pub/sub subscriptions) are called sequentially in the given order, then
+// client is closed. All of it is bounded by timeout (if positive): the ctx of
+// the flush functions is cancelled when 3/4 of timeout is exceeded, leaving
+// the rest for closing the client, and if client.Close does not return by
+// then, it is abandoned (see WithTimeout).
+//
+// A failing flush function does not prevent closing the client. Errors of the
+// flush functions and of closing the client are recorded as the error of the
+// hook (see Errors and HooksErr).
+func TrackRedisClient(name string, client io.Closer, timeout time.Duration, flush ...func(ctx context.Context) error) {
+	std.TrackRedisClient(name, client, timeout, flush...)
+}
+
+// TrackRedisClient registers a Redis-like client to be flushed and closed in
+// PhaseStop. See the package-level TrackRedisClient.
+func (m *Manager) TrackRedisClient(name string, client io.Closer, timeout time.Duration, flush ...func(ctx context.Context) error) {
+	m.OnPhaseError(PhaseStop, func() error {
+		var errs []error
+		callTimeout(timeout-timeout/4, func(ctx context.Context) error {
+			for i, f := range flush {
+				if err := f(ctx); err != nil {
+					errs = append(errs, fmt.Errorf("flush %s (#%d): %w", name, i+1, err))
+				}
+			}
+			return nil
+		})
+
+		if err := client.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", name, err))
+		}
+		return errors.Join(errs...)
+	}, WithName(name), WithTimeout(timeout))
+}
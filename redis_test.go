@@ -0,0 +1,59 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeCloser is an io.Closer recording its close.
+type fakeCloser struct {
+	r *recorder
+}
+
+func (c fakeCloser) Close() error {
+	c.r.add("close")
+	return nil
+}
+
+func TestTrackRedisClient(t *testing.T) {
+	m := newTestManager()
+	r := &recorder{}
+	errFlush := errors.New("flush failed")
+	m.OnPhase(PhaseDrain, r.addFunc("drain"))
+	m.OnPhase(PhaseCleanup, r.addFunc("cleanup"))
+	m.TrackRedisClient("redis", fakeCloser{r}, time.Second,
+		func(ctx context.Context) error { r.add("pipeline"); return errFlush },
+		func(ctx context.Context) error { r.add("pubsub"); return nil },
+	)
+
+	// Flush errors are recorded as hook errors.
+	if err := m.Close(); !errors.Is(err, errFlush) {
+		t.Errorf("Close returned %v, want %v", err, errFlush)
+	}
+	if got, want := r.String(), "drain,pipeline,pubsub,close,cleanup"; got != want {
+		t.Errorf("events are %q, want %q", got, want)
+	}
+}
+
+func TestTrackRedisClientStuckFlush(t *testing.T) {
+	m := newTestManager()
+	r := &recorder{}
+	m.TrackRedisClient("redis", fakeCloser{r}, 100*time.Millisecond,
+		func(ctx context.Context) error {
+			<-ctx.Done() // Stuck until the flush budget is exceeded.
+			return ctx.Err()
+		},
+	)
+
+	err := m.Close()
+	// The client is still closed within the timeout of the hook.
+	if got := r.String(); got != "close" {
+		t.Errorf("events are %q, want %q", got, "close")
+	}
+	var he *HookError
+	if !errors.As(err, &he) || he.TimedOut || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close returned %v, want the flush deadline error without the hook timing out", err)
+	}
+}
@@ -0,0 +1,138 @@
+package shutdown
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime/debug"
+)
+
+// ReloadC returns the reload channel. A value is sent on it when a reload
+// signal (SIGHUP by default on Unix, see WithReloadSignals) is received.
+// Reloads not yet received are coalesced: the channel has a buffer of 1,
+// and sending on it does not block.
+//
+// Calling ReloadC enables reload: unless set by WithReloadSignals,
+// the default reload signals are subscribed to.
+func ReloadC() <-chan struct{} { return std.ReloadC() }
+
+// ReloadC returns the reload channel. See the package-level ReloadC.
+func (m *Manager) ReloadC() <-chan struct{} {
+	m.enableReload()
+	return m.reloadCh
+}
+
+// WithReloadSignals sets the OS signals that trigger a reload, and enables
+// reload. If no signals are provided, no reload signals are handled.
+//
+// Reload is opt-in: without this option, reload signals are only handled once
+// OnReload or ReloadC is called, in which case SIGHUP is used on Unix (there is
+// no default reload signal on other platforms). Until then, reload signals keep
+// their default behavior (e.g. SIGHUP terminates the app).
+//
+// Reload is distinct from shutdown: on a reload signal, a value is sent on
+// ReloadC, and the functions registered with OnReload are called.
+func WithReloadSignals(sigs ...os.Signal) Option {
+	return func(m *Manager) {
+		m.reloadSignals = sigs
+		m.reloadConfigured = true
+	}
+}
+
+// OnReload registers f to be called when a reload signal is received, e.g.
+// to reload the configuration. Registered functions are called sequentially
+// in registration order, in a goroutine dedicated to reloads.
+// A panic in f is logged, and does not affect the rest of the functions.
+//
+// Calling OnReload enables reload, see ReloadC.
+func OnReload(f func()) { std.OnReload(f) }
+
+// OnReload registers f to be called when a reload signal is received.
+// See the package-level OnReload.
+func (m *Manager) OnReload(f func()) {
+	m.enableReload()
+
+	m.mu.Lock()
+	m.reloadHooks = append(m.reloadHooks, f)
+	m.mu.Unlock()
+}
+
+// reloadSub is a subscription to reload signals.
+type reloadSub struct {
+	// ch is a signal channel used to receive signals.
+	ch chan os.Signal
+
+	// stop is closed to unsubscribe.
+	stop chan struct{}
+}
+
+// enableReload subscribes to the default reload signals, unless reload signals
+// have been set by WithReloadSignals.
+func (m *Manager) enableReload() {
+	m.reloadOnce.Do(func() {
+		if !m.reloadConfigured {
+			m.reloadSignals = defaultReloadSignals
+			m.reloadConfigured = true
+			m.subscribeReload()
+		}
+	})
+}
+
+// subscribeReload subscribes to m.reloadSignals, replacing the previous subscription.
+func (m *Manager) subscribeReload() {
+	old := m.reloadSub
+	m.reloadSub = nil
+	if len(m.reloadSignals) > 0 {
+		sub := &reloadSub{ch: make(chan os.Signal, 1), stop: make(chan struct{})}
+		signal.Notify(sub.ch, m.reloadSignals...)
+		m.reloadSub = sub
+		go m.handleReloads(sub)
+	}
+	if old != nil {
+		close(old.stop)
+	}
+}
+
+// handleReloads handles the signals of sub until it is stopped.
+func (m *Manager) handleReloads(sub *reloadSub) {
+	defer signal.Stop(sub.ch)
+
+	for {
+		select {
+		case sig := <-sub.ch:
+			m.logf("Received '%v' signal, reloading...", sig)
+			m.reload()
+		case <-sub.stop:
+			return
+		}
+	}
+}
+
+// reload notifies ReloadC and calls the reload hooks.
+func (m *Manager) reload() {
+	select {
+	case m.reloadCh <- struct{}{}:
+	default:
+	}
+
+	m.mu.Lock()
+	fs := m.reloadHooks
+	m.mu.Unlock()
+
+	for i, f := range fs {
+		m.callRecover(fmt.Sprintf("reload hook #%d", i+1), f)
+	}
+}
+
+// callRecover calls f, recovering from a panic.
+// name is used in logs.
+func (m *Manager) callRecover(name string, f func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.errorf("Panic in %s: %v\n%s", name, r, debug.Stack())
+		}
+	}()
+	defer m.warnBlocking(name)()
+
+	f()
+}
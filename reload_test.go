@@ -0,0 +1,30 @@
+package shutdown
+
+import "testing"
+
+func TestReload(t *testing.T) {
+	m := newTestManager(WithReloadSignals())
+	r := &recorder{}
+	m.OnReload(r.addFunc("a"))
+	m.OnReload(func() { panic("boom") })
+	m.OnReload(r.addFunc("b"))
+
+	m.reload()
+	m.reload()
+
+	// Hooks are called on each reload, in registration order,
+	// reloads not yet received from ReloadC are coalesced.
+	if got, want := r.String(), "a,b,a,b"; got != want {
+		t.Errorf("reload hooks called %q, want %q", got, want)
+	}
+	<-m.ReloadC()
+	select {
+	case <-m.ReloadC():
+		t.Error("reloads not coalesced")
+	default:
+	}
+	if m.Initiated() {
+		t.Error("reload initiated shutdown")
+	}
+	m.Close()
+}
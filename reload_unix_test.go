@@ -0,0 +1,43 @@
+//go:build unix
+
+package shutdown
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReloadSignal(t *testing.T) {
+	if inChild(t) {
+		m := New(WithSignals(syscall.SIGTERM))
+		reloaded := make(chan struct{}, 1)
+		m.OnReload(func() { reloaded <- struct{}{} })
+
+		raise(syscall.SIGHUP)
+		select {
+		case <-m.ReloadC():
+		case <-time.After(5 * time.Second):
+			os.Exit(3)
+		}
+		<-reloaded
+		if m.Initiated() {
+			os.Exit(4)
+		}
+
+		raise(syscall.SIGTERM)
+		<-m.C
+		m.Wait()
+		os.Exit(m.ExitCode())
+	}
+
+	code, out := runChild(t)
+	if code != 143 {
+		t.Errorf("child exited with %d, want 143, output:\n%s", code, out)
+	}
+	if !strings.Contains(out, "reloading...") {
+		t.Errorf("reload not logged, output:\n%s", out)
+	}
+}
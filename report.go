@@ -0,0 +1,62 @@
+package shutdown
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// WithReportDir makes the final Wait write a report of the shutdown (the
+// Status as JSON) to a new file in dir, named after the completion time and
+// the process id, e.g. "shutdown-20260102T150405.000Z-1234.json", so recent
+// shutdowns can be compared in post-incident reviews without a log pipeline.
+//
+// Only the last keep reports are kept (older ones are removed), keep <= 0
+// means all reports are kept. dir is not created, it must exist.
+func WithReportDir(dir string, keep int) Option {
+	return func(m *Manager) {
+		m.reportDir = dir
+		m.reportKeep = keep
+	}
+}
+
+// reportPattern is the glob pattern of report file names.
+const reportPattern = "shutdown-*.json"
+
+// writeReport writes the shutdown report and applies the retention,
+// if a report directory is set (see WithReportDir).
+func (m *Manager) writeReport() {
+	if m.reportDir == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(m.Status(), "", "\t")
+	if err != nil {
+		m.errorf("Failed to marshal shutdown report: %v", err)
+		return
+	}
+	name := fmt.Sprintf("shutdown-%s-%d.json", time.Now().UTC().Format("20060102T150405.000Z"), os.Getpid())
+	if err := writeFileAtomic(filepath.Join(m.reportDir, name), append(data, '\n')); err != nil {
+		m.errorf("Failed to write shutdown report: %v", err)
+		return
+	}
+
+	if m.reportKeep <= 0 {
+		return
+	}
+	names, err := filepath.Glob(filepath.Join(m.reportDir, reportPattern))
+	if err != nil {
+		return // Only possible error is ErrBadPattern
+	}
+	// Names start with the time in a fixed format, so they sort chronologically.
+	sort.Strings(names)
+	for len(names) > m.reportKeep {
+		if err := os.Remove(names[0]); err != nil {
+			m.errorf("Failed to remove old shutdown report: %v", err)
+		}
+		names = names[1:]
+	}
+}
@@ -0,0 +1,41 @@
+package shutdown
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReportDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"shutdown-20000101T000000.000Z-1.json", "shutdown-20000102T000000.000Z-1.json", "other.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := newTestManager(WithReportDir(dir, 2))
+	m.InitiateManual()
+	m.Wait()
+
+	names, _ := filepath.Glob(filepath.Join(dir, reportPattern))
+	if len(names) != 2 || filepath.Base(names[0]) != "shutdown-20000102T000000.000Z-1.json" {
+		t.Fatalf("reports are %v, want the newest old one and the new one", names)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "other.json")); err != nil {
+		t.Errorf("unrelated file removed: %v", err)
+	}
+
+	data, err := os.ReadFile(names[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st Status
+	if err := json.Unmarshal(data, &st); err != nil {
+		t.Fatalf("invalid report %s: %v", data, err)
+	}
+	if st.State != StateCompleted || len(st.Causes) != 1 || st.Causes[0] != "manual" {
+		t.Errorf("report is %+v, want completed with cause manual", st)
+	}
+}
@@ -0,0 +1,67 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RetryPolicy describes how Retry retries.
+type RetryPolicy struct {
+	// Attempts is the max number of attempts. 0 means unlimited.
+	Attempts int
+
+	// Delay is the initial delay between attempts.
+	Delay time.Duration
+
+	// MaxDelay is the max delay between attempts. 0 means no limit.
+	MaxDelay time.Duration
+
+	// Multiplier is applied to the delay after each attempt.
+	// Values less than 1 are treated as 1 (constant delay).
+	Multiplier float64
+}
+
+// Retry calls fn until it succeeds or the attempts of policy are exhausted,
+// sleeping between attempts as defined by policy. ctx is passed to fn.
+//
+// Backoff sleeps are aborted immediately when shutdown is initiated,
+// in which case an error wrapping ErrInitiated is returned (so retry loops
+// don't hold up draining). If ctx is done, ctx.Err() is returned.
+// If attempts are exhausted, the last error of fn is returned.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	return std.Retry(ctx, policy, fn)
+}
+
+// Retry calls fn until it succeeds or the attempts of policy are exhausted,
+// aborting backoff sleeps on shutdown. See the package-level Retry.
+func (m *Manager) Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) error {
+	delay := policy.Delay
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if policy.Attempts > 0 && attempt >= policy.Attempts {
+			return err
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-m.C:
+			t.Stop()
+			return fmt.Errorf("%w (last error: %v)", ErrInitiated, err)
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+
+		if policy.Multiplier > 1 {
+			delay = time.Duration(float64(delay) * policy.Multiplier)
+		}
+		if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	m := newTestManager()
+	errFn := errors.New("failed")
+
+	attempts := 0
+	err := m.Retry(context.Background(), RetryPolicy{Attempts: 3, Delay: time.Millisecond}, func(ctx context.Context) error {
+		attempts++
+		if attempts < 2 {
+			return errFn
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Errorf("got %v after %d attempts, want success after 2", err, attempts)
+	}
+
+	attempts = 0
+	err = m.Retry(context.Background(), RetryPolicy{Attempts: 3, Delay: time.Millisecond}, func(ctx context.Context) error {
+		attempts++
+		return errFn
+	})
+	if err != errFn || attempts != 3 {
+		t.Errorf("got %v after %d attempts, want %v after 3", err, attempts, errFn)
+	}
+	m.Close()
+}
+
+func TestRetryBackoff(t *testing.T) {
+	m := newTestManager()
+	var times []time.Time
+	policy := RetryPolicy{Attempts: 4, Delay: 10 * time.Millisecond, MaxDelay: 25 * time.Millisecond, Multiplier: 2}
+	m.Retry(context.Background(), policy, func(ctx context.Context) error {
+		times = append(times, time.Now())
+		return errors.New("failed")
+	})
+
+	// Delays: 10ms, 20ms, 25ms (capped).
+	for i, min := range []time.Duration{10, 20, 25} {
+		if d := times[i+1].Sub(times[i]); d < min*time.Millisecond {
+			t.Errorf("delay #%d is %v, want at least %vms", i+1, d, min)
+		}
+	}
+	m.Close()
+}
+
+func TestRetryShutdown(t *testing.T) {
+	m := newTestManager()
+	errFn := errors.New("failed")
+	time.AfterFunc(20*time.Millisecond, m.InitiateManual)
+
+	start := time.Now()
+	err := m.Retry(context.Background(), RetryPolicy{Delay: time.Hour}, func(ctx context.Context) error { return errFn })
+	if !errors.Is(err, ErrInitiated) {
+		t.Errorf("got %v, want %v", err, ErrInitiated)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("backoff sleep not aborted on shutdown (took %v)", d)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m2 := newTestManager()
+	if err := m2.Retry(ctx, RetryPolicy{Delay: time.Hour}, func(ctx context.Context) error { return errFn }); err != context.Canceled {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+	m.Wait()
+	m2.Close()
+}
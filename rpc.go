@@ -0,0 +1,146 @@
+package shutdown
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/rpc"
+	"sync"
+	"time"
+)
+
+// RPCServer is a GracefulServer serving net/rpc connections accepted on a
+// listener. The standard library provides no graceful stop for rpc servers,
+// RPCServer adds one.
+//
+// Shutdown stops accepting new connections, stops reading new requests from
+// open connections, and waits for in-flight calls to complete and their
+// responses to be sent. Close closes the listener and all open connections.
+//
+// Use it with Manage:
+//
+//	shutdown.Manage("RPC server", &shutdown.RPCServer{Listener: l})
+type RPCServer struct {
+	// Server is the rpc server to serve. If nil, rpc.DefaultServer is used.
+	Server *rpc.Server
+
+	// Listener to accept connections on.
+	Listener net.Listener
+
+	// NewCodec is an optional function to create a codec for a connection,
+	// e.g. jsonrpc.NewServerCodec. If nil, the default gob codec is used.
+	NewCodec func(conn io.ReadWriteCloser) rpc.ServerCodec
+
+	mu      sync.Mutex
+	closing bool
+	conns   map[net.Conn]struct{}
+	wg      sync.WaitGroup // Tracks served connections
+}
+
+// Serve accepts connections on the listener and serves them,
+// each in its own goroutine.
+func (s *RPCServer) Serve() error {
+	srv := s.Server
+	if srv == nil {
+		srv = rpc.DefaultServer
+	}
+
+	for {
+		conn, err := s.Listener.Accept()
+		if err != nil {
+			if s.isClosing() {
+				return nil
+			}
+			return err
+		}
+		if !s.track(conn) {
+			conn.Close()
+			continue
+		}
+
+		go func() {
+			defer s.untrack(conn)
+			if s.NewCodec != nil {
+				srv.ServeCodec(s.NewCodec(conn))
+			} else {
+				srv.ServeConn(conn)
+			}
+		}()
+	}
+}
+
+// Shutdown gracefully shuts down the server: it closes the listener and waits
+// for in-flight calls of open connections to complete. If ctx expires first,
+// ctx.Err() is returned, and connections are left open (use Close to close them).
+func (s *RPCServer) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closing = true
+	err := s.Listener.Close()
+	for conn := range s.conns {
+		// Unblock pending reads: rpc stops reading requests, waits for
+		// pending responses to be sent, then closes the connection.
+		conn.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close closes the listener (if not yet closed) and all open connections.
+func (s *RPCServer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if !s.closing {
+		s.closing = true
+		err = s.Listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	return err
+}
+
+// isClosing tells if Shutdown or Close has been called.
+func (s *RPCServer) isClosing() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closing
+}
+
+// track registers conn as open. Returns false if the server is closing,
+// in which case conn is not registered.
+func (s *RPCServer) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closing {
+		return false
+	}
+	if s.conns == nil {
+		s.conns = map[net.Conn]struct{}{}
+	}
+	s.conns[conn] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+// untrack unregisters conn.
+func (s *RPCServer) untrack(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+	s.wg.Done()
+}
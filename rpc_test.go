@@ -0,0 +1,112 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"testing"
+	"time"
+)
+
+// Slow is an rpc service with a slow method.
+type Slow struct {
+	started chan struct{}
+}
+
+// Sleep sleeps d, then returns it in reply.
+func (s *Slow) Sleep(d time.Duration, reply *time.Duration) error {
+	s.started <- struct{}{}
+	time.Sleep(d)
+	*reply = d
+	return nil
+}
+
+// startRPC starts serving an RPCServer with the Slow service using m.
+// If json is true, the JSON-RPC codec is used.
+func startRPC(t *testing.T, m *Manager, json bool) (addr string, slow *Slow) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow = &Slow{started: make(chan struct{}, 1)}
+	srv := rpc.NewServer()
+	if err := srv.Register(slow); err != nil {
+		t.Fatal(err)
+	}
+	s := &RPCServer{Server: srv, Listener: l}
+	if json {
+		s.NewCodec = jsonrpc.NewServerCodec
+	}
+	m.Manage("rpc server", s)
+	return l.Addr().String(), slow
+}
+
+func TestRPCServer(t *testing.T) {
+	for _, json := range []bool{false, true} {
+		m := newTestManager()
+		addr, slow := startRPC(t, m, json)
+
+		dial := rpc.Dial
+		if json {
+			dial = jsonrpc.Dial
+		}
+		client, err := dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var reply time.Duration
+		call := client.Go("Slow.Sleep", 100*time.Millisecond, &reply, nil)
+		<-slow.started
+
+		m.Close()
+
+		// In-flight calls complete, and their responses are sent.
+		<-call.Done
+		if call.Error != nil || reply != 100*time.Millisecond {
+			t.Errorf("json: %t, in-flight call returned %v, %v", json, reply, call.Error)
+		}
+		client.Close()
+
+		// New connections are not accepted.
+		if c, err := net.DialTimeout("tcp", addr, time.Second); err == nil {
+			c.Close()
+			t.Errorf("json: %t, connection accepted after shutdown", json)
+		}
+	}
+}
+
+func TestRPCServerShutdownTimeout(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow := &Slow{started: make(chan struct{}, 1)}
+	srv := rpc.NewServer()
+	srv.Register(slow)
+	s := &RPCServer{Server: srv, Listener: l}
+	serveDone := make(chan error, 1)
+	go func() { serveDone <- s.Serve() }()
+
+	client, err := rpc.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	var reply time.Duration
+	client.Go("Slow.Sleep", time.Second, &reply, nil)
+	<-slow.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown returned %v, want %v", err, context.DeadlineExceeded)
+	}
+	if err := <-serveDone; err != nil {
+		t.Errorf("Serve returned %v after Shutdown", err)
+	}
+	s.Close()
+}
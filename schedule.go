@@ -0,0 +1,70 @@
+package shutdown
+
+import (
+	"sort"
+	"time"
+)
+
+// Step is a step of an escalation Schedule.
+type Step struct {
+	// After is the time offset of the step from the shutdown initiation.
+	After time.Duration
+
+	// Name of the step, used in logs.
+	Name string
+
+	// Do performs the step.
+	Do func()
+}
+
+// Schedule is an escalation timeline executed when shutdown is initiated,
+// for example:
+//
+//	shutdown.SetSchedule(shutdown.Schedule{
+//		{After: 0, Name: "readiness off", Do: readiness.Off},
+//		{After: 5 * time.Second, Name: "cancel soft", Do: cancelSoft},
+//		{After: 20 * time.Second, Name: "cancel hard", Do: cancelHard},
+//		{After: 25 * time.Second, Name: "force exit", Do: func() { os.Exit(1) }},
+//	})
+//
+// This makes the whole escalation policy visible in one place.
+type Schedule []Step
+
+// SetSchedule sets the escalation schedule to be executed when shutdown is
+// initiated. Steps are executed in a separate goroutine sequentially,
+// in the order of their After, each at (or after) its time offset from the
+// shutdown initiation. Remaining steps are skipped if the final Wait returns.
+// A panicking step is logged, and it doesn't stop the remaining steps.
+//
+// SetSchedule must be called before shutdown is initiated.
+func SetSchedule(s Schedule) { std.SetSchedule(s) }
+
+// SetSchedule sets the escalation schedule. See the package-level SetSchedule.
+func (m *Manager) SetSchedule(s Schedule) {
+	s = append(Schedule(nil), s...)
+	sort.SliceStable(s, func(i, j int) bool { return s[i].After < s[j].After })
+
+	m.mu.Lock()
+	m.schedule = s
+	m.mu.Unlock()
+}
+
+// runSchedule executes the escalation schedule.
+func (m *Manager) runSchedule() {
+	m.mu.Lock()
+	s, from := m.schedule, m.initiatedAt
+	m.mu.Unlock()
+
+	for _, step := range s {
+		t := time.NewTimer(time.Until(from.Add(step.After)))
+		select {
+		case <-t.C:
+		case <-m.waitDone:
+			t.Stop()
+			return
+		}
+
+		m.logf("Escalation step (t+%v): %s", step.After, step.Name)
+		m.callRecover("escalation step "+step.Name, step.Do)
+	}
+}
@@ -0,0 +1,44 @@
+package shutdown
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	m := newTestManager()
+	r := &recorder{}
+	done := make(chan struct{})
+	m.SetSchedule(Schedule{
+		{After: 20 * time.Millisecond, Name: "last", Do: func() { r.add("last"); close(done) }},
+		{After: 0, Name: "first", Do: r.addFunc("first")},
+		{After: 10 * time.Millisecond, Name: "panicking", Do: func() { panic("boom") }},
+	})
+
+	start := time.Now()
+	m.InitiateManual()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("schedule did not complete")
+	}
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Errorf("last step executed after %v, want at least 20ms", d)
+	}
+	if got, want := r.String(), "first,last"; got != want {
+		t.Errorf("events are %q, want %q", got, want)
+	}
+	m.Close()
+}
+
+func TestScheduleSkippedAfterWait(t *testing.T) {
+	m := newTestManager()
+	r := &recorder{}
+	m.SetSchedule(Schedule{{After: 50 * time.Millisecond, Name: "late", Do: r.addFunc("late")}})
+
+	m.Close()
+	time.Sleep(100 * time.Millisecond)
+	if got := r.String(); got != "" {
+		t.Errorf("events are %q, want none", got)
+	}
+}
@@ -0,0 +1,207 @@
+package shutdown
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// WithServerShutdownTimeout sets the max time a server registered with Manage
+// (or ManageLast / ManageMetrics) is waited for to shut down gracefully.
+// The default is 20 seconds.
+func WithServerShutdownTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.serverShutdownTimeout = timeout
+	}
+}
+
+// GracefulServer is a server that can be shut down gracefully.
+//
+// For example an *http.Server can be adapted like this:
+//
+//	type httpServer struct{ *http.Server }
+//
+//	func (s httpServer) Serve() error { return s.ListenAndServe() }
+type GracefulServer interface {
+	// Serve serves until an error occurs or until it is shut down.
+	Serve() error
+
+	// Shutdown gracefully shuts down the server.
+	Shutdown(ctx context.Context) error
+}
+
+// Manage starts serving s in a new goroutine, and shuts it down when shutdown
+// is initiated. name is used in logs.
+//
+// Manage follows the same flow as the web server example:
+// if s stops serving before shutdown is initiated, that is not normal,
+// and a manual shutdown is initiated (making sure the whole app gets terminated).
+// On shutdown the server is shut down gracefully, waiting no longer than
+// the server shutdown timeout (see WithServerShutdownTimeout). If that fails and s also implements io.Closer,
+// a forceful shutdown is attempted using its Close method.
+func Manage(name string, s GracefulServer) { std.Manage(name, s) }
+
+// Manage starts serving s in a new goroutine, and shuts it down when shutdown
+// is initiated. See the package-level Manage.
+func (m *Manager) Manage(name string, s GracefulServer) {
+	m.Go(func() {
+		if err := s.Serve(); err != nil && !m.Initiated() {
+			m.errorf("Abnormal %s shut down with error: %v", name, err)
+		}
+		if !m.Initiated() {
+			// If we got to this point, that's not normal:
+			m.warnf("%s stopped serving, initiating manual system shutdown:", name)
+			m.InitiateManual()
+		}
+	})
+
+	m.Go(func() {
+		// Wait for a shutdown event (either signal or manual)
+		<-m.C
+
+		m.logf("Stopping %s (system shutdown)...", name)
+		m.shutdownServer(name, s)
+	})
+}
+
+// shutdownServer shuts down s gracefully, waiting no longer than
+// m.serverShutdownTimeout. If that fails and s also implements io.Closer,
+// a forceful shutdown is attempted using its Close method.
+func (m *Manager) shutdownServer(name string, s GracefulServer) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.serverShutdownTimeout)
+	err := s.Shutdown(ctx)
+	cancel() // Call cancel to release resources of the context
+
+	if err != nil {
+		m.errorf("Failed to shut down %s gracefully: %v", name, err)
+		if closer, ok := s.(io.Closer); ok {
+			// Try forceful shutdown:
+			if err := closer.Close(); err != nil {
+				m.errorf("%s forceful shutdown error: %v", name, err)
+			}
+		}
+		return
+	}
+	m.logf("%s gracefully shut down.", name)
+}
+
+// namedServer is a server with a name used in logs.
+type namedServer struct {
+	name string
+	s    GracefulServer
+
+	// scraped is signaled on scrapes of metrics servers, nil for other servers.
+	scraped chan struct{}
+}
+
+// ManageLast starts serving s in a new goroutine, and keeps it serving until
+// the very end of shutdown: it is shut down only by Wait, after all goroutines
+// registered in Wg have finished. name is used in logs.
+//
+// It is meant for debug / pprof servers, so operators can still inspect a
+// process that is stuck mid-teardown. Unlike Manage, if s stops serving,
+// it is only logged (no shutdown is initiated).
+func ManageLast(name string, s GracefulServer) { std.ManageLast(name, s) }
+
+// ManageLast starts serving s in a new goroutine, and keeps it serving until
+// the very end of shutdown. See the package-level ManageLast.
+func (m *Manager) ManageLast(name string, s GracefulServer) {
+	m.mu.Lock()
+	m.lastServers = append(m.lastServers, namedServer{name: name, s: s})
+	m.mu.Unlock()
+
+	go func() {
+		if err := s.Serve(); err != nil && !m.Initiated() {
+			m.errorf("Abnormal %s shut down with error: %v", name, err)
+		}
+	}()
+}
+
+// WithMetricsScrapeWindow sets the max time a server registered with
+// ManageMetrics keeps serving after all other goroutines and hooks have
+// finished, waiting for a final scrape. The default is 15 seconds.
+func WithMetricsScrapeWindow(window time.Duration) Option {
+	return func(m *Manager) {
+		m.metricsScrapeWindow = window
+	}
+}
+
+// ManageMetrics is like ManageLast, but it is meant for metrics endpoints
+// (e.g. Prometheus): when the final Wait shuts it down, it keeps serving until
+// a final scrape is observed or no longer than the scrape window (see
+// WithMetricsScrapeWindow), so the last counter values of the process are captured.
+//
+// newServer is called with the scraped function to create the server, before
+// it starts serving. The metrics handler of the server must call scraped after
+// serving a scrape, e.g.:
+//
+//	shutdown.ManageMetrics("metrics server", func(scraped func()) shutdown.GracefulServer {
+//		return httpServer{&http.Server{Addr: ":9090", Handler: http.HandlerFunc(
+//			func(w http.ResponseWriter, r *http.Request) {
+//				promhttp.Handler().ServeHTTP(w, r)
+//				scraped()
+//			})}}
+//	})
+func ManageMetrics(name string, newServer func(scraped func()) GracefulServer) {
+	std.ManageMetrics(name, newServer)
+}
+
+// ManageMetrics is like ManageLast, but it is meant for metrics endpoints.
+// See the package-level ManageMetrics.
+func (m *Manager) ManageMetrics(name string, newServer func(scraped func()) GracefulServer) {
+	ch := make(chan struct{}, 1)
+	s := newServer(func() {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	})
+
+	m.mu.Lock()
+	m.lastServers = append(m.lastServers, namedServer{name: name, s: s, scraped: ch})
+	m.mu.Unlock()
+
+	go func() {
+		if err := s.Serve(); err != nil && !m.Initiated() {
+			m.errorf("Abnormal %s shut down with error: %v", name, err)
+		}
+	}()
+}
+
+// shutdownLastServers shuts down servers registered with ManageLast
+// and ManageMetrics.
+func (m *Manager) shutdownLastServers() {
+	m.mu.Lock()
+	servers := m.lastServers
+	m.lastServers = nil
+	m.mu.Unlock()
+
+	for _, ns := range servers {
+		if ns.scraped != nil {
+			m.waitFinalScrape(ns)
+		}
+		m.logf("Stopping %s (last)...", ns.name)
+		m.shutdownServer(ns.name, ns.s)
+	}
+}
+
+// waitFinalScrape waits for a scrape of the metrics server ns,
+// no longer than m.metricsScrapeWindow.
+func (m *Manager) waitFinalScrape(ns namedServer) {
+	// Scrapes observed earlier do not count:
+	select {
+	case <-ns.scraped:
+	default:
+	}
+
+	m.logf("Waiting for final scrape of %s...", ns.name)
+	t := time.NewTimer(m.metricsScrapeWindow)
+	defer t.Stop()
+
+	select {
+	case <-ns.scraped:
+	case <-t.C:
+		m.warnf("No final scrape of %s within %v.", ns.name, m.metricsScrapeWindow)
+	case <-m.hurry:
+	}
+}
@@ -0,0 +1,132 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeServer is a GracefulServer serving until it is shut down.
+type fakeServer struct {
+	serve    func()
+	shutdown chan struct{}
+}
+
+func newFakeServer(serve func()) *fakeServer {
+	return &fakeServer{serve: serve, shutdown: make(chan struct{})}
+}
+
+func (s *fakeServer) Serve() error {
+	if s.serve != nil {
+		s.serve()
+	}
+	<-s.shutdown
+	return nil
+}
+
+func (s *fakeServer) Shutdown(ctx context.Context) error {
+	close(s.shutdown)
+	return nil
+}
+
+func TestManageMetrics(t *testing.T) {
+	m := newTestManager(WithMetricsScrapeWindow(10 * time.Second))
+
+	var srv *fakeServer
+	stop := make(chan struct{})
+	m.ManageMetrics("metrics", func(scraped func()) GracefulServer {
+		srv = newFakeServer(func() {
+			// Scrapes may arrive as soon as the server is serving.
+			scraped()
+			go func() {
+				for {
+					select {
+					case <-stop:
+						return
+					case <-time.After(10 * time.Millisecond):
+						scraped()
+					}
+				}
+			}()
+		})
+		return srv
+	})
+
+	start := time.Now()
+	m.Close()
+	close(stop)
+
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("final scrape was not observed, Close took %v", d)
+	}
+	select {
+	case <-srv.shutdown:
+	default:
+		t.Error("metrics server was not shut down")
+	}
+}
+
+func TestManageLast(t *testing.T) {
+	m := newTestManager()
+	srv := newFakeServer(nil)
+	m.ManageLast("debug", srv)
+
+	// A goroutine still draining: the server must keep serving meanwhile.
+	m.Wg.Add(1)
+	go func() {
+		defer m.Wg.Done()
+		<-m.C
+		time.Sleep(30 * time.Millisecond)
+		select {
+		case <-srv.shutdown:
+			t.Error("last server shut down before Wg was done")
+		default:
+		}
+	}()
+
+	m.InitiateManual()
+	m.Wait()
+
+	select {
+	case <-srv.shutdown:
+	default:
+		t.Error("last server was not shut down by Wait")
+	}
+}
+
+func TestManage(t *testing.T) {
+	m := newTestManager()
+	srv := newFakeServer(nil)
+	m.Manage("api", srv)
+
+	m.InitiateManual()
+	m.Wait()
+
+	select {
+	case <-srv.shutdown:
+	default:
+		t.Error("server was not shut down on shutdown")
+	}
+}
+
+// failingServer is a GracefulServer that stops serving right away.
+type failingServer struct{}
+
+func (failingServer) Serve() error                   { return errors.New("listen failed") }
+func (failingServer) Shutdown(context.Context) error { return nil }
+
+func TestManageStoppedServing(t *testing.T) {
+	m := newTestManager()
+	m.Manage("api", failingServer{})
+
+	select {
+	case <-m.C:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server stopping serving did not initiate shutdown")
+	}
+	if r := m.Reason(); r != "manual" {
+		t.Errorf("Reason is %q, want %q", r, "manual")
+	}
+	m.Wait()
+}
@@ -0,0 +1,102 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Service adapts a component to suture-style supervision trees: it has
+// Serve(ctx) and Stop methods, and each run of the service gets its own
+// Manager, so a restart by the supervisor is a scoped restart of the
+// component (its hooks, servers and goroutines) instead of a process shutdown.
+// Use NewService to create one.
+//
+// For example, with suture:
+//
+//	sup := suture.NewSimple("app")
+//	sup.Add(shutdown.NewService("api", func(m *shutdown.Manager) error {
+//		m.Manage("api server", httpServer{&http.Server{Addr: ":8080"}})
+//		return nil
+//	}))
+//	sup.Serve(shutdown.Context)
+type Service struct {
+	name string
+	run  func(m *Manager) error
+	opts []Option
+
+	mu      sync.Mutex
+	m       *Manager // m is the Manager of the current run, nil if not running
+	stopped bool     // stopped tells if Stop was called during the current run
+}
+
+// NewService creates a new Service. name is used in logs and errors.
+//
+// run is called with a new Manager (created with opts, see New) on each run
+// of the service: it should register the resources of the run on m (e.g.
+// servers with Manage, goroutines with Go, hooks with OnShutdown) and return.
+// A non-nil error fails the run.
+func NewService(name string, run func(m *Manager) error, opts ...Option) *Service {
+	return &Service{name: name, run: run, opts: opts}
+}
+
+// Serve runs the service: it creates a new Manager bound to ctx (see
+// BindContext), calls run with it, and waits until the shutdown of the Manager
+// is initiated, then closes it (running its hooks, see Close).
+//
+// Shutdown of the run is initiated when ctx is done, when Stop is called, or
+// by the Manager itself (e.g. a server registered with Manage stopped
+// serving). In the last case Serve returns an error, so the supervisor
+// restarts the service. Otherwise Serve returns the errors of the hooks of
+// the run (see HooksErr).
+func (s *Service) Serve(ctx context.Context) error {
+	m := New(s.opts...)
+	m.BindContext(ctx)
+
+	s.mu.Lock()
+	s.m, s.stopped = m, false
+	s.mu.Unlock()
+
+	if err := s.run(m); err != nil {
+		m.InitiateError(err)
+		m.Close()
+		s.done()
+		return fmt.Errorf("service %s: %w", s.name, err)
+	}
+
+	<-m.C
+	hooksErr := m.Close()
+	stopped := s.done()
+	if ctx.Err() == nil && !stopped {
+		return fmt.Errorf("service %s stopped: %s", s.name, m.Reason())
+	}
+	return hooksErr
+}
+
+// done marks the current run done, and tells if it was stopped by Stop.
+func (s *Service) done() (stopped bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.m = nil
+	return s.stopped
+}
+
+// Stop initiates the shutdown of the current run, if any, making Serve return.
+func (s *Service) Stop() {
+	s.mu.Lock()
+	m := s.m
+	if m != nil {
+		s.stopped = true
+	}
+	s.mu.Unlock()
+
+	if m != nil {
+		m.InitiateManualReason("service stopped")
+	}
+}
+
+// String returns the name of the service.
+func (s *Service) String() string {
+	return s.name
+}
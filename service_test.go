@@ -0,0 +1,85 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestService(t *testing.T) {
+	var managers []*Manager
+	var cleanups int
+	s := NewService("worker", func(m *Manager) error {
+		managers = append(managers, m)
+		m.OnShutdown(func() { cleanups++ })
+		if len(managers) == 1 {
+			// First run fails on its own after starting.
+			time.AfterFunc(10*time.Millisecond, func() { m.InitiateManualReason("lost connection") })
+		}
+		return nil
+	}, WithLogger(log.New(io.Discard, "", 0)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A run shut down by its own Manager is an error, so it is restarted.
+	err := s.Serve(ctx)
+	if err == nil || !strings.Contains(err.Error(), "lost connection") {
+		t.Errorf("Serve returned %v, want the cause of the failure", err)
+	}
+
+	// A restart gets a new Manager; Stop ends the run without an error.
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx) }()
+	for {
+		s.mu.Lock()
+		running := s.m != nil
+		s.mu.Unlock()
+		if running {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	s.Stop()
+	if err := <-done; err != nil {
+		t.Errorf("Serve returned %v after Stop, want nil", err)
+	}
+
+	// Cancelling ctx ends the run without an error.
+	go func() { done <- s.Serve(ctx) }()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Serve returned %v after ctx was cancelled, want nil", err)
+	}
+
+	if len(managers) != 3 || managers[0] == managers[1] || managers[1] == managers[2] {
+		t.Errorf("got managers %v, want a new one for each of the 3 runs", managers)
+	}
+	if cleanups != 3 {
+		t.Errorf("hooks run %d times, want 3 (once per run)", cleanups)
+	}
+	if s.String() != "worker" {
+		t.Errorf("String is %q, want %q", s.String(), "worker")
+	}
+}
+
+func TestServiceRunError(t *testing.T) {
+	errInit := errors.New("init failed")
+	cleaned := false
+	s := NewService("worker", func(m *Manager) error {
+		m.OnShutdown(func() { cleaned = true })
+		return errInit
+	}, WithLogger(log.New(io.Discard, "", 0)))
+
+	if err := s.Serve(context.Background()); !errors.Is(err, errInit) {
+		t.Errorf("Serve returned %v, want %v", err, errInit)
+	}
+	if !cleaned {
+		t.Error("hooks of the failed run not run")
+	}
+}
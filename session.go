@@ -0,0 +1,115 @@
+package shutdown
+
+import (
+	"sync"
+	"time"
+)
+
+// SessionDrainer drains the sessions of services with sticky sessions:
+// instead of cancelling all sessions at once on shutdown, it calls a
+// per-session notify function (e.g. to tell the client to reconnect elsewhere),
+// spreading the calls over the drain window with rate limiting, so the load
+// of reconnects on the other instances is spread, too.
+//
+// Create one with NewSessionDrainer, and register sessions with Add and Remove.
+type SessionDrainer[S comparable] struct {
+	m         *Manager
+	window    time.Duration
+	perSecond int
+	notify    func(s S)
+
+	mu       sync.Mutex
+	sessions map[S]struct{}
+}
+
+// NewSessionDrainer creates a new SessionDrainer, and registers its drain as a
+// hook of PhaseDrain. When shutdown is initiated, notify is called for each
+// session registered at the time, spread evenly over window, but no more than
+// perSecond times per second (0 means no limit). If window is 0 and there is no
+// limit, all sessions are notified without waiting. Sessions removed in the
+// meantime are not notified. The hook completes when all sessions have been
+// notified (or removed).
+func NewSessionDrainer[S comparable](window time.Duration, perSecond int, notify func(s S)) *SessionDrainer[S] {
+	return NewSessionDrainerOn(std, window, perSecond, notify)
+}
+
+// NewSessionDrainerOn is like NewSessionDrainer, but the drain is registered
+// as a hook of m.
+func NewSessionDrainerOn[S comparable](m *Manager, window time.Duration, perSecond int, notify func(s S)) *SessionDrainer[S] {
+	d := &SessionDrainer[S]{
+		m:         m,
+		window:    window,
+		perSecond: perSecond,
+		notify:    notify,
+		sessions:  map[S]struct{}{},
+	}
+	m.OnPhase(PhaseDrain, d.drain, WithName("session drain"))
+	return d
+}
+
+// Add registers the session s. After shutdown has been initiated, new sessions
+// are refused: false is returned, and s is not registered.
+func (d *SessionDrainer[S]) Add(s S) bool {
+	if d.m.Initiated() {
+		return false
+	}
+
+	d.mu.Lock()
+	d.sessions[s] = struct{}{}
+	d.mu.Unlock()
+	return true
+}
+
+// Remove unregisters the session s (e.g. when it ends).
+func (d *SessionDrainer[S]) Remove(s S) {
+	d.mu.Lock()
+	delete(d.sessions, s)
+	d.mu.Unlock()
+}
+
+// drain notifies the registered sessions.
+func (d *SessionDrainer[S]) drain() {
+	d.mu.Lock()
+	ss := make([]S, 0, len(d.sessions))
+	for s := range d.sessions {
+		ss = append(ss, s)
+	}
+	d.mu.Unlock()
+
+	if len(ss) == 0 {
+		return
+	}
+
+	interval := d.window / time.Duration(len(ss))
+	if d.perSecond > 0 {
+		if minInterval := time.Second / time.Duration(d.perSecond); interval < minInterval {
+			interval = minInterval
+		}
+	}
+	var tick <-chan time.Time // nil if sessions are notified without waiting
+	if interval > 0 {
+		d.m.logf("Draining %d session(s) (one per %v)...", len(ss), interval)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	} else {
+		d.m.logf("Draining %d session(s)...", len(ss))
+	}
+
+	notified := false
+	for _, s := range ss {
+		d.mu.Lock()
+		_, ok := d.sessions[s]
+		d.mu.Unlock()
+		if !ok {
+			continue // Removed in the meantime
+		}
+
+		if notified && tick != nil {
+			<-tick
+		}
+		d.Remove(s)
+		d.notify(s)
+		notified = true
+	}
+}
@@ -0,0 +1,86 @@
+package shutdown
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// drainSessions registers sessions 1..n with a SessionDrainer created with
+// window and perSecond, removes the sessions in removed, shuts down,
+// and returns the notified sessions (sorted) and the time the shutdown took.
+func drainSessions(t *testing.T, window time.Duration, perSecond, n int, removed ...int) ([]int, time.Duration) {
+	t.Helper()
+
+	m := newTestManager()
+	mu := sync.Mutex{}
+	var notified []int
+	d := NewSessionDrainerOn(m, window, perSecond, func(s int) {
+		mu.Lock()
+		notified = append(notified, s)
+		mu.Unlock()
+	})
+	for s := 1; s <= n; s++ {
+		if !d.Add(s) {
+			t.Fatalf("Add(%d) refused before shutdown", s)
+		}
+	}
+	for _, s := range removed {
+		d.Remove(s)
+	}
+
+	start := time.Now()
+	m.InitiateManual()
+	<-m.C
+	m.Wait()
+	elapsed := time.Since(start)
+
+	if errs := m.Errors(); errs != nil {
+		t.Fatalf("hook errors: %v", errs)
+	}
+	if d.Add(n + 1) {
+		t.Error("Add after shutdown admitted")
+	}
+	sort.Ints(notified)
+	return notified, elapsed
+}
+
+func TestSessionDrainer(t *testing.T) {
+	notified, elapsed := drainSessions(t, 100*time.Millisecond, 0, 4, 2)
+	if want := []int{1, 3, 4}; !equalInts(notified, want) {
+		t.Errorf("notified %v, want %v", notified, want)
+	}
+	// 3 sessions spread over 100ms: one per 25ms.
+	if elapsed < 40*time.Millisecond {
+		t.Errorf("drain took %v, notifications were not spread", elapsed)
+	}
+}
+
+func TestSessionDrainerRateLimit(t *testing.T) {
+	// The window allows one per 1ms, the limit one per 20ms.
+	_, elapsed := drainSessions(t, 4*time.Millisecond, 50, 4)
+	if elapsed < 50*time.Millisecond {
+		t.Errorf("drain took %v, rate limit not respected", elapsed)
+	}
+}
+
+func TestSessionDrainerNoWindow(t *testing.T) {
+	notified, _ := drainSessions(t, 0, 0, 3)
+	if want := []int{1, 2, 3}; !equalInts(notified, want) {
+		t.Errorf("notified %v, want %v", notified, want)
+	}
+}
+
+// equalInts tells if a and b are equal.
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
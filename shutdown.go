@@ -2,63 +2,693 @@ package shutdown
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
 	"os"
-	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 )
 
+// Manager manages the shutdown of an app (or of a part of it).
+// Use New to create one.
+//
+// The package-level functions and variables operate on a default Manager
+// (which is created when the package is initialized). Helpers operating on
+// another Manager are available as methods (e.g. Manager.Sleep), or for
+// generic helpers, as functions with the On suffix taking the Manager
+// (e.g. CollectOn).
+type Manager struct {
+	// Context is cancelled on shutdown.
+	Context context.Context
+
+	// C is the shutdown channel, closed on shutdown.
+	C <-chan struct{}
+
+	// Wg is the WaitGroup goroutines may use to "register" themselves
+	// if they wish to be waited for on shutdown.
+	Wg *sync.WaitGroup
+
+	// reloadCh is the reload channel, see ReloadC.
+	reloadCh chan struct{}
+
+	cancel context.CancelCauseFunc
+
+	// logger is used to log shutdown events.
+	logger Logger
+
+	// graceTimeout is the max time the final Wait waits for Wg after initiation, 0 means no timeout.
+	graceTimeout time.Duration
+
+	// signals holds the OS signals initiating a shutdown.
+	signals []os.Signal
+
+	// sigSrc is the source subscribed to signals, nil if no signals are handled.
+	sigSrc *signalSource
+
+	// reloadSignals holds the OS signals triggering a reload.
+	reloadSignals []os.Signal
+
+	// reloadConfigured tells if reloadSignals has been set by WithReloadSignals.
+	reloadConfigured bool
+
+	// reloadOnce is used to enable reload only once, see enableReload.
+	reloadOnce sync.Once
+
+	// reloadSub is the subscription to reloadSignals, nil if no reload signals are handled.
+	reloadSub *reloadSub
+
+	// quitDiagnostics tells if SIGQUIT dumps goroutine stacks and initiates shutdown.
+	quitDiagnostics bool
+
+	// quitSubscribed tells if SIGQUIT has been subscribed to for diagnostics.
+	quitSubscribed bool
+
+	// fastExit tells if the app is exited on a signal when nothing is registered.
+	fastExit bool
+
+	// watchdog is the timeout of the watchdog, 0 means no watchdog.
+	watchdog time.Duration
+
+	// debounce is the debounce window of identical signals.
+	debounce time.Duration
+
+	// hardKill is the timeout of the hard kill timer, 0 means no timer.
+	hardKill time.Duration
+
+	// confirmWindow is the confirm window of shutdown signals.
+	confirmWindow time.Duration
+
+	// holdsReleased is signaled when all holds have been released.
+	holdsReleased chan struct{}
+
+	// statusFile is the path of the status file, see WithStatusFile.
+	statusFile string
+
+	// statusFileMu serializes status file updates.
+	statusFileMu sync.Mutex
+
+	// reportDir is the directory of shutdown reports, see WithReportDir.
+	reportDir string
+
+	// reportKeep is the number of shutdown reports to keep, see WithReportDir.
+	reportKeep int
+
+	// autoExit tells if the app is exited when shutdown completes.
+	autoExit bool
+
+	// escalation holds the actions taken on signals received during shutdown.
+	escalation []Escalation
+
+	// forceExitCode is the exit code of forced exits, see WithForceExitCode.
+	forceExitCode int
+
+	// holdTimeout is the max time holds delay the broadcast, see WithHoldTimeout.
+	holdTimeout time.Duration
+
+	// bestEffortTimeout is the max time best-effort hooks are waited for, see WithBestEffortTimeout.
+	bestEffortTimeout time.Duration
+
+	// cgoExitTimeout is the max time a forced exit waits for PhaseCgo, see WithCgoExitTimeout.
+	cgoExitTimeout time.Duration
+
+	// blockingThreshold is the threshold of blocking callback warnings, see WithBlockingThreshold.
+	blockingThreshold time.Duration
+
+	// serverShutdownTimeout is the max time servers are waited for to shut down, see WithServerShutdownTimeout.
+	serverShutdownTimeout time.Duration
+
+	// metricsScrapeWindow is the max time to wait for a final scrape, see WithMetricsScrapeWindow.
+	metricsScrapeWindow time.Duration
+
+	// leadershipTimeout is the max time to wait for leadership resignations, see WithLeadershipTimeout.
+	leadershipTimeout time.Duration
+
+	// grpcClientDrainTimeout is the max time to wait for critical RPCs, see WithGRPCClientDrainTimeout.
+	grpcClientDrainTimeout time.Duration
+
+	// httpClientDrainTimeout is the max time to wait for outbound HTTP requests, see WithHTTPClientDrainTimeout.
+	httpClientDrainTimeout time.Duration
+
+	// jobObject tells if managed child processes are placed in job objects, see WithJobObject.
+	jobObject bool
+
+	// runPendingFuncs tells if pending AfterFunc callbacks are run on shutdown, see WithRunPendingFuncs.
+	runPendingFuncs bool
+
+	// suspendAware tells if deadlines don't count suspensions, see WithSuspendAwareness.
+	suspendAware bool
+
+	// clockOnce is used to start watching the clock only once, see deadline.
+	clockOnce sync.Once
+
+	// hurry is closed when shutdown is escalated to skip remaining waits.
+	hurry chan struct{}
+
+	// hooksDone is closed when all hooks have completed.
+	hooksDone chan struct{}
+
+	// waitOnce is used to perform the final wait only once.
+	waitOnce sync.Once
+
+	// closeOnce is used to release the signal subscriptions only once, see Close.
+	closeOnce sync.Once
+
+	// lastGaspOnce is used to call the last-gasp function only once, see SetLastGasp.
+	lastGaspOnce sync.Once
+
+	// lastGaspMu guards lastGasp. It is separate from mu, so the hard kill
+	// timer can get the last-gasp function even if mu is held by a stuck goroutine.
+	lastGaspMu sync.Mutex
+
+	// lastGasp is the function set with SetLastGasp.
+	lastGasp func(reason string)
+
+	// waitDone is closed when the final Wait returns.
+	waitDone chan struct{}
+
+	// mainCh is used to send functions to be run by MainLoop.
+	mainCh chan func()
+
+	// mainLoopDone is closed when MainLoop returns.
+	mainLoopDone chan struct{}
+
+	// mu protects the fields below.
+	mu sync.Mutex
+
+	// duringSignals is the number of signals received during shutdown.
+	duringSignals int
+
+	// hurried tells if hurry has been closed.
+	hurried bool
+
+	// signal is the signal that initiated the shutdown, nil if not a signal.
+	signal os.Signal
+
+	// signalAt is the time when the signal that initiated the shutdown was received.
+	signalAt time.Time
+
+	// cancelledAt is the time when Context was cancelled.
+	cancelledAt time.Time
+
+	// firstHookAt is the time when the first shutdown hook started.
+	firstHookAt time.Time
+
+	// err is the first error passed to InitiateError.
+	err error
+
+	// exitCode is the exit code set by SetExitCode, nil if not set.
+	exitCode *int
+
+	// lastSig is the last received shutdown signal.
+	lastSig os.Signal
+
+	// lastSigAt is the time when lastSig was received.
+	lastSigAt time.Time
+
+	// hardKillTimer is the armed hard kill timer.
+	hardKillTimer *time.Timer
+
+	// lastClockObs is the time of the last clock observation, see observeClock.
+	lastClockObs time.Time
+
+	// suspended is the total time the system was detected to be suspended during shutdown.
+	suspended time.Duration
+
+	// exiting tells if an exit has begun, see beginExit.
+	exiting bool
+
+	// cgoDone is closed when PhaseCgo completes, nil if it has not started.
+	cgoDone chan struct{}
+
+	// abortCh is closed by Abort to abort the pending shutdown, nil if no shutdown is pending.
+	abortCh chan struct{}
+
+	// holds is the number of holds not yet released, see Hold.
+	holds int
+
+	// phase is the phase being executed.
+	phase Phase
+
+	// hooksTaken tells if hooks can't be registered anymore, see OnShutdown.
+	hooksTaken bool
+
+	// hooksTotal is the number of hooks to run.
+	hooksTotal int
+
+	// hooksCompleted is the number of completed hooks.
+	hooksCompleted int
+
+	// guards is the number of open critical sections, see Enter.
+	guards int
+
+	// tasks is the number of running goroutines started by Go and of units of work admitted by Gates.
+	tasks int
+
+	// taskSites holds the number of running goroutines started by Go, by call site (see callSite).
+	taskSites map[string]int
+
+	// completed tells if the final Wait has completed (waitDone is closed).
+	completed bool
+
+	// hookStatuses holds the statuses of the hooks to run, in run order, see Status.
+	hookStatuses []HookStatus
+
+	// drainTimes holds the drain times of tasks and the run times of hooks, see DrainTimes.
+	drainTimes []DrainTime
+
+	// initiatedAt is the time when shutdown was initiated.
+	initiatedAt time.Time
+
+	// causes holds the causes of all initiation attempts, first is the one that initiated the shutdown.
+	causes []string
+
+	// lastCoalescedLog is the time when an ignored initiation was last logged.
+	lastCoalescedLog time.Time
+
+	// suppressedLogs is the number of ignored initiations not logged since lastCoalescedLog.
+	suppressedLogs int
+
+	// reloadHooks holds the functions registered with OnReload.
+	reloadHooks []func()
+
+	// leaderships holds the registered leadership handles.
+	leaderships []leadership
+
+	// hooks holds the registered shutdown hooks.
+	hooks []hook
+
+	// deferred holds the functions registered with Defer.
+	deferred []hook
+
+	// phaseHooks holds the hooks registered with OnPhase.
+	phaseHooks map[Phase][]hook
+
+	// phaseStarts and phaseEnds hold the callbacks registered with
+	// OnPhaseStart and OnPhaseEnd.
+	phaseStarts, phaseEnds map[Phase][]func()
+
+	// classLimits holds the concurrency limits of resource classes.
+	classLimits map[string]int
+
+	// hookErrors holds the errors of hooks.
+	hookErrors []*HookError
+
+	// waiting tells if the final Wait has been called, see addRefused.
+	waiting bool
+
+	// lastServers holds servers to be shut down last.
+	lastServers []namedServer
+
+	// schedule is the escalation schedule.
+	schedule Schedule
+}
+
+// New creates a new Manager configured with the given options.
+// By default it handles no shutdown signals (see WithSignals), so independent
+// Managers (e.g. in tests) don't react to the signals of the process; only the
+// default Manager handles SIGTERM and SIGINT. Reload signals are only handled
+// if reload is used (see WithReloadSignals).
+func New(opts ...Option) *Manager {
+	m := &Manager{
+		Wg:                     &sync.WaitGroup{},
+		logger:                 defaultLogger{},
+		reloadCh:               make(chan struct{}, 1),
+		escalation:             []Escalation{EscalateExit},
+		forceExitCode:          2,
+		holdTimeout:            5 * time.Second,
+		cgoExitTimeout:         5 * time.Second,
+		bestEffortTimeout:      100 * time.Millisecond,
+		blockingThreshold:      time.Second,
+		serverShutdownTimeout:  20 * time.Second,
+		metricsScrapeWindow:    15 * time.Second,
+		leadershipTimeout:      10 * time.Second,
+		grpcClientDrainTimeout: 20 * time.Second,
+		httpClientDrainTimeout: 20 * time.Second,
+		hurry:                  make(chan struct{}),
+		hooksDone:              make(chan struct{}),
+		holdsReleased:          make(chan struct{}, 1),
+		waitDone:               make(chan struct{}),
+		mainCh:                 make(chan func()),
+		mainLoopDone:           make(chan struct{}),
+		phaseHooks:             map[Phase][]hook{},
+		taskSites:              map[string]int{},
+		phaseStarts:            map[Phase][]func(){},
+		phaseEnds:              map[Phase][]func(){},
+		classLimits:            map[string]int{},
+	}
+	m.Context, m.cancel = context.WithCancelCause(context.Background())
+	m.C = m.Context.Done()
+
+	m.configure(opts)
+
+	return m
+}
+
 var (
-	// sigch is a signal channel used to receive SIGTERM and SIGINT (CTRL+C).
-	// Buffered to make sure we don't miss it (send on it is non-blocking).
-	sigch = make(chan os.Signal, 1)
+	// std is the default Manager.
+	std = New(WithSignals(syscall.SIGTERM, syscall.SIGINT))
+
+	// appStartedAt is the time when the app started (when the package was initialized).
+	appStartedAt = time.Now()
 )
 
 var (
 	// Context's channel is cancelled on shutdown
-	Context, cancel = context.WithCancel(context.Background())
+	Context = std.Context
 
 	// C is the shutdown channel.
-	C <-chan struct{} = Context.Done()
+	C <-chan struct{} = std.C
 
 	// Wg is the shared WaitGroup goroutines may use to "register" themselves
 	// if they wish to be waited for on app shutdown.
-	Wg = &sync.WaitGroup{}
+	Wg = std.Wg
 )
 
-func init() {
-	// Register sigch for SIGTERM and SIGINT.
-	signal.Notify(sigch, syscall.SIGTERM, syscall.SIGINT)
+// ErrInitiated is the error returned by functions of the package that are
+// aborted because shutdown has been initiated.
+var ErrInitiated = errors.New("shutdown initiated")
 
-	go func() {
-		defer signal.Stop(sigch)
+// ErrUnknown is the error recorded when InitiateError is called with a nil error.
+var ErrUnknown = errors.New("unknown error")
 
-		s := <-sigch
-		// We only subscribed to signals to which we have to shutdown
-		log.Printf("Received '%v' signal, broadcasting shutdown...", s)
+// Cause describes the trigger of the shutdown. It is set as the cause of
+// Context, so consumers of the context can use context.Cause to get it:
+//
+//	var c *shutdown.Cause
+//	if errors.As(context.Cause(ctx), &c) && c.Signal == syscall.SIGINT {
+//		// ...
+//	}
+//
+// A Cause matches ErrInitiated using errors.Is, and it unwraps to Err.
+type Cause struct {
+	// Text describes the cause, e.g. "signal: terminated" or "manual" (see Reason).
+	Text string
 
-		cancel()
-	}()
+	// Signal is the signal that initiated the shutdown, nil if not a signal.
+	Signal os.Signal
+
+	// Err is the error passed to InitiateError, or the cause of the cancellation
+	// of a context bound with BindContext, nil otherwise.
+	Err error
+}
+
+// Error implements error.
+func (c *Cause) Error() string {
+	return "shutdown initiated: " + c.Text
+}
+
+// Is tells if target is ErrInitiated.
+func (c *Cause) Is(target error) bool {
+	return target == ErrInitiated
+}
+
+// Unwrap returns Err.
+func (c *Cause) Unwrap() error {
+	return c.Err
+}
+
+// Default returns the default Manager, used by the package-level functions.
+func Default() *Manager {
+	return std
+}
+
+// startInitiation records an initiation attempt with the given cause.
+// It returns true if this is the first attempt, in which case the caller must
+// call broadcast. Subsequent attempts are coalesced into the first one:
+// their causes are recorded, and they are logged (rate-limited).
+func (m *Manager) startInitiation(cause string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.causes = append(m.causes, cause)
+	if len(m.causes) == 1 {
+		m.initiatedAt = time.Now()
+		// Register the hook runner (see broadcast) right away, so a final Wait
+		// called before the broadcast also waits for the hooks, and it doesn't
+		// race with a Wg.Wait already in progress.
+		m.Wg.Add(1)
+		if timeout := m.hardKill; timeout > 0 {
+			m.hardKillTimer = time.AfterFunc(timeout, func() { m.hardKillExit(timeout) })
+		}
+		return true
+	}
+
+	if time.Since(m.lastCoalescedLog) < time.Second {
+		m.suppressedLogs++
+		return false
+	}
+	if m.suppressedLogs > 0 {
+		m.warnf("Shutdown already initiated, ignoring: %s (and %d more)", cause, m.suppressedLogs)
+	} else {
+		m.warnf("Shutdown already initiated, ignoring: %s", cause)
+	}
+	m.lastCoalescedLog, m.suppressedLogs = time.Now(), 0
+	return false
+}
+
+// broadcast starts the escalation schedule, waits for holds, hands off
+// leaderships, broadcasts the shutdown by cancelling Context with cause,
+// and runs the shutdown hooks and the watchdog.
+func (m *Manager) broadcast(cause *Cause) {
+	m.mu.Lock()
+	m.signal = cause.Signal
+	m.mu.Unlock()
+
+	go m.runSchedule()
+
+	m.waitHolds()
+	m.resignLeaderships()
+
+	// Hooks registered from now on are not run (see OnShutdown).
+	m.mu.Lock()
+	m.hooksTaken = true
+	m.mu.Unlock()
+
+	// The hook runner has been registered in Wg by startInitiation.
+	m.cancel(cause)
+	cancelledAt := time.Now()
+	m.mu.Lock()
+	m.cancelledAt = cancelledAt
+	m.mu.Unlock()
+	m.updateStatusFile()
+	go m.runHooks()
+	go m.runWatchdog()
 }
 
 // InitiateManual initiates a manual shutdown.
-func InitiateManual() {
-	log.Println("Manual shutdown initiated...")
+func InitiateManual() { std.InitiateManual() }
 
-	// Imit a SIGTERM signal. Do non-blocking send!
-	select {
-	case sigch <- syscall.SIGTERM:
-	default:
+// InitiateManual initiates a manual shutdown.
+func (m *Manager) InitiateManual() {
+	if m.startInitiation("manual") {
+		m.noticef("Manual shutdown initiated...")
+		go m.broadcast(&Cause{Text: "manual"})
 	}
 }
 
-// Initiated tells if a shutdown has been initiated, either by a signal or manually.
-func Initiated() bool {
+// InitiateManualReason initiates a manual shutdown with the given reason
+// (e.g. "failed health check" or "admin request"), which is logged,
+// and is recorded in the cause as "manual: " followed by the reason
+// (see Reason and Causes).
+func InitiateManualReason(reason string) { std.InitiateManualReason(reason) }
+
+// InitiateManualReason initiates a manual shutdown with the given reason.
+// See the package-level InitiateManualReason.
+func (m *Manager) InitiateManualReason(reason string) {
+	if cause := "manual: " + reason; m.startInitiation(cause) {
+		m.noticef("Manual shutdown initiated: %s...", reason)
+		go m.broadcast(&Cause{Text: cause})
+	}
+}
+
+// Abort aborts a pending shutdown during the confirm window (see WithConfirmWindow).
+// It returns true if a pending shutdown was aborted, false if there was none.
+func Abort() bool { return std.Abort() }
+
+// Abort aborts a pending shutdown during the confirm window. See the package-level Abort.
+func (m *Manager) Abort() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.abortCh == nil {
+		return false
+	}
+	close(m.abortCh)
+	m.abortCh = nil
+	return true
+}
+
+// InitiateAfter schedules a shutdown to be initiated after d (e.g. for a nightly
+// restart), with the cause "scheduled" (see Reason). The returned cancel function
+// aborts the scheduled shutdown if it has not yet been initiated. Calling cancel
+// more than once has no additional effect.
+//
+// If shutdown is initiated by other means in the meantime, the scheduled
+// shutdown is dropped.
+func InitiateAfter(d time.Duration) (cancel func()) { return std.InitiateAfter(d) }
+
+// InitiateAfter schedules a shutdown to be initiated after d.
+// See the package-level InitiateAfter.
+func (m *Manager) InitiateAfter(d time.Duration) (cancel func()) {
+	m.logf("Shutdown scheduled in %v.", d)
+
+	cancelCh := make(chan struct{})
+	go func() {
+		t := time.NewTimer(d)
+		defer t.Stop()
+
+		select {
+		case <-t.C:
+			if m.startInitiation("scheduled") {
+				m.noticef("Scheduled shutdown initiated...")
+				m.broadcast(&Cause{Text: "scheduled"})
+			}
+		case <-cancelCh:
+			m.logf("Scheduled shutdown cancelled.")
+		case <-m.C:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(cancelCh) })
+	}
+}
+
+// InitiateError initiates a shutdown because of a fatal error. The error is
+// logged, and it is recorded in the cause as "error: " followed by the error
+// (see Reason and Causes). The first error passed to InitiateError is returned
+// by Err, even if shutdown has already been initiated by other means.
+//
+// A nil err is replaced with ErrUnknown, so Err (and the exit code, see
+// ExitCode) still reflects the failure.
+func InitiateError(err error) { std.InitiateError(err) }
+
+// InitiateError initiates a shutdown because of a fatal error.
+// See the package-level InitiateError.
+func (m *Manager) InitiateError(err error) {
+	if err == nil {
+		err = ErrUnknown
+	}
+
+	m.mu.Lock()
+	if m.err == nil {
+		m.err = err
+	}
+	m.mu.Unlock()
+
+	if cause := fmt.Sprintf("error: %v", err); m.startInitiation(cause) {
+		m.errorf("Shutdown initiated due to error: %v", err)
+		go m.broadcast(&Cause{Text: cause, Err: err})
+	}
+}
+
+// Err returns the first error passed to InitiateError,
+// nil if InitiateError has not been called (e.g. for signal shutdowns).
+func Err() error { return std.Err() }
+
+// Err returns the first error passed to InitiateError. See the package-level Err.
+func (m *Manager) Err() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.err
+}
+
+// Signal returns the signal that initiated the shutdown, nil if shutdown has
+// not been initiated or it was not initiated by a signal (e.g. manually).
+func Signal() os.Signal { return std.Signal() }
+
+// Signal returns the signal that initiated the shutdown. See the package-level Signal.
+func (m *Manager) Signal() os.Signal {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.signal
+}
+
+// Reason returns the cause of the initiation that initiated the shutdown,
+// e.g. "signal: terminated" or "manual: failed health check".
+// Returns an empty string if shutdown has not been initiated.
+func Reason() string { return std.Reason() }
+
+// Reason returns the cause of the initiation that initiated the shutdown.
+// See the package-level Reason.
+func (m *Manager) Reason() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.causes) == 0 {
+		return ""
+	}
+	return m.causes[0]
+}
+
+// Causes returns the causes of all initiation attempts (e.g. "manual" or
+// "signal: terminated"). The first is the one that initiated the shutdown,
+// the rest were coalesced into it. Returns nil if shutdown has not been initiated.
+func Causes() []string { return std.Causes() }
+
+// Causes returns the causes of all initiation attempts. See the package-level Causes.
+func (m *Manager) Causes() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.causes == nil {
+		return nil
+	}
+	return append([]string(nil), m.causes...)
+}
+
+// Initiated tells if a shutdown has been initiated, either by a signal, manually or by a Source.
+func Initiated() bool { return std.Initiated() }
+
+// Initiated tells if a shutdown has been initiated, either by a signal, manually or by a Source.
+func (m *Manager) Initiated() bool {
 	select {
-	case <-C:
+	case <-m.C:
 		return true
 	default:
 	}
 	return false
 }
+
+// Uptime returns the time elapsed since the app started
+// (more precisely since the package was initialized).
+func Uptime() time.Duration {
+	return time.Since(appStartedAt)
+}
+
+// StartedAt returns the time when shutdown was initiated,
+// the zero time if shutdown has not been initiated.
+//
+// It is the anchor of the shutdown deadlines (the grace timeout and the
+// watchdog), and it carries a monotonic clock reading, so budgets derived
+// from it, e.g. time.Until(shutdown.StartedAt().Add(budget)), are not affected
+// by wall clock (NTP) adjustments. With suspend awareness the deadlines are
+// measured on the wall clock instead, see WithSuspendAwareness.
+func StartedAt() time.Time { return std.StartedAt() }
+
+// StartedAt returns the time when shutdown was initiated. See the package-level StartedAt.
+func (m *Manager) StartedAt() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.initiatedAt
+}
+
+// Duration returns the time elapsed since shutdown was initiated,
+// 0 if shutdown has not been initiated.
+// Calling it at the end of shutdown (e.g. right before returning from main())
+// tells how long the shutdown took.
+func Duration() time.Duration { return std.Duration() }
+
+// Duration returns the time elapsed since shutdown was initiated.
+// See the package-level Duration.
+func (m *Manager) Duration() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.initiatedAt.IsZero() {
+		return 0
+	}
+	return time.Since(m.initiatedAt)
+}
@@ -0,0 +1,221 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// newTestManager creates a Manager for tests, discarding its logs.
+func newTestManager(opts ...Option) *Manager {
+	return New(append([]Option{WithLogger(log.New(io.Discard, "", 0))}, opts...)...)
+}
+
+// childEnv is the environment variable holding the name of the test to be
+// run in a child process, see runChild.
+const childEnv = "SHUTDOWN_TEST_CHILD"
+
+// inChild tells if the test t is running in a child process started by runChild.
+// In the child, the default Manager stops handling signals, so only the
+// Managers of the test react to them.
+func inChild(t *testing.T) bool {
+	if os.Getenv(childEnv) != t.Name() {
+		return false
+	}
+	Init(WithSignals())
+	return true
+}
+
+// runChild runs the test t in a child process (the test binary itself), and
+// returns the exit code and the output of the child. The test must check
+// inChild, and act as the app (e.g. send signals to itself using raise) in
+// the child. The test is skipped on Windows, as signals can't be sent there.
+func runChild(t *testing.T) (code int, out string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("sending signals is not supported on windows")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, os.Args[0], "-test.run=^"+t.Name()+"$", "-test.v")
+	cmd.Env = append(os.Environ(), childEnv+"="+t.Name())
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Fatalf("running child failed: %v", err)
+	}
+	return cmd.ProcessState.ExitCode(), string(output)
+}
+
+// raise sends sig to the current process.
+func raise(sig os.Signal) {
+	p, err := os.FindProcess(os.Getpid())
+	if err == nil {
+		err = p.Signal(sig)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestClose(t *testing.T) {
+	m := newTestManager()
+	errHook := errors.New("hook failed")
+	m.OnShutdownError(func() error { return errHook }, WithName("failing"))
+
+	if err := m.Close(); !errors.Is(err, errHook) {
+		t.Errorf("Close returned %v, want %v", err, errHook)
+	}
+	if got := m.Reason(); got != "close" {
+		t.Errorf("Reason is %q, want %q", got, "close")
+	}
+	select {
+	case <-m.Completed():
+	default:
+		t.Error("Completed not closed after Close")
+	}
+
+	// Closing again has no additional effect.
+	if err := m.Close(); !errors.Is(err, errHook) {
+		t.Errorf("second Close returned %v, want %v", err, errHook)
+	}
+}
+
+func TestInitiateErrorNil(t *testing.T) {
+	m := newTestManager()
+	m.InitiateError(nil)
+
+	if err := m.Err(); !errors.Is(err, ErrUnknown) {
+		t.Errorf("Err is %v, want %v", err, ErrUnknown)
+	}
+	if got := m.ExitCode(); got != 1 {
+		t.Errorf("ExitCode is %d, want 1", got)
+	}
+	if got, want := m.Reason(), "error: unknown error"; got != want {
+		t.Errorf("Reason is %q, want %q", got, want)
+	}
+	m.Close()
+}
+
+func TestInitiateAfter(t *testing.T) {
+	m := newTestManager()
+	start := time.Now()
+	m.InitiateAfter(30 * time.Millisecond)
+	<-m.C
+	if d := time.Since(start); d < 30*time.Millisecond {
+		t.Errorf("shutdown initiated after %v, want at least 30ms", d)
+	}
+	if got := m.Reason(); got != "scheduled" {
+		t.Errorf("Reason is %q, want %q", got, "scheduled")
+	}
+	m.Wait()
+}
+
+func TestInitiateAfterCancel(t *testing.T) {
+	m := newTestManager()
+	cancel := m.InitiateAfter(20 * time.Millisecond)
+	cancel()
+	cancel() // No additional effect.
+
+	time.Sleep(50 * time.Millisecond)
+	if m.Initiated() {
+		t.Error("cancelled scheduled shutdown initiated")
+	}
+	m.Close()
+}
+
+func TestInitiateAfterOtherMeans(t *testing.T) {
+	m := newTestManager()
+	m.InitiateAfter(20 * time.Millisecond)
+	m.InitiateManual()
+	<-m.C
+
+	time.Sleep(50 * time.Millisecond)
+	if got := m.Causes(); len(got) != 1 || got[0] != "manual" {
+		t.Errorf("Causes are %q, want [manual] (scheduled shutdown dropped)", got)
+	}
+	m.Wait()
+}
+
+func TestUptimeDuration(t *testing.T) {
+	m := newTestManager()
+	for _, c := range []struct {
+		name string
+		init bool
+	}{{"before initiation", false}, {"after initiation", true}} {
+		if c.init {
+			m.InitiateManual()
+			<-m.C
+			time.Sleep(10 * time.Millisecond)
+		}
+		if up := Uptime(); up <= 0 {
+			t.Errorf("%s: Uptime is %v, want positive", c.name, up)
+		}
+		d := m.Duration()
+		if !c.init && d != 0 {
+			t.Errorf("%s: Duration is %v, want 0", c.name, d)
+		}
+		if c.init && (d < 10*time.Millisecond || d > Uptime()) {
+			t.Errorf("%s: Duration is %v, want at least 10ms and at most the uptime", c.name, d)
+		}
+	}
+	m.Wait()
+}
+
+func TestStartedAt(t *testing.T) {
+	m := newTestManager()
+	if at := m.StartedAt(); !at.IsZero() {
+		t.Errorf("StartedAt before initiation is %v, want zero", at)
+	}
+
+	before := time.Now()
+	m.InitiateManual()
+	after := time.Now()
+	at := m.StartedAt()
+	if at.Before(before) || at.After(after) {
+		t.Errorf("StartedAt is %v, want between %v and %v", at, before, after)
+	}
+
+	// Not changed by further initiation attempts.
+	m.InitiateManualReason("again")
+	if at2 := m.StartedAt(); !at2.Equal(at) {
+		t.Errorf("StartedAt changed from %v to %v", at, at2)
+	}
+	m.Wait()
+}
+
+// fakeSignalSource is a Source reporting sig as the received signal.
+type fakeSignalSource struct {
+	sig os.Signal
+}
+
+func (s fakeSignalSource) Wait(ctx context.Context) bool    { return true }
+func (s fakeSignalSource) received() (os.Signal, time.Time) { return s.sig, time.Now() }
+func (s fakeSignalSource) String() string                   { return "signal: " + s.sig.String() }
+
+func TestSignal(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		initiate func(m *Manager)
+		want     os.Signal
+	}{
+		{"not initiated", func(m *Manager) {}, nil},
+		{"manual", func(m *Manager) { m.InitiateManual(); <-m.C }, nil},
+		{"signal", func(m *Manager) { m.AddSource(fakeSignalSource{os.Interrupt}); <-m.C }, os.Interrupt},
+	} {
+		m := newTestManager()
+		c.initiate(m)
+		if sig := m.Signal(); sig != c.want {
+			t.Errorf("%s: Signal is %v, want %v", c.name, sig, c.want)
+		}
+		m.Close()
+	}
+}
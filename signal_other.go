@@ -0,0 +1,14 @@
+//go:build !unix
+
+package shutdown
+
+import "os"
+
+// defaultReloadSignals holds the OS signals triggering a reload when reload is
+// enabled by OnReload or ReloadC (see WithReloadSignals). There is no
+// conventional reload signal on non-Unix platforms.
+var defaultReloadSignals []os.Signal
+
+// quitSignal is the signal dumping goroutine stacks, see WithQuitDiagnostics.
+// nil as there is no SIGQUIT on non-Unix platforms.
+var quitSignal os.Signal
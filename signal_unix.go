@@ -0,0 +1,15 @@
+//go:build unix
+
+package shutdown
+
+import (
+	"os"
+	"syscall"
+)
+
+// defaultReloadSignals holds the OS signals triggering a reload when reload is
+// enabled by OnReload or ReloadC (see WithReloadSignals).
+var defaultReloadSignals = []os.Signal{syscall.SIGHUP}
+
+// quitSignal is the signal dumping goroutine stacks, see WithQuitDiagnostics.
+var quitSignal os.Signal = syscall.SIGQUIT
@@ -0,0 +1,200 @@
+package shutdown
+
+import (
+	"sync"
+	"time"
+)
+
+// Sleep pauses the current goroutine for at least the duration d,
+// or until shutdown is initiated, whichever happens first.
+// It returns true if the full duration was slept, false if it returned early
+// because of a shutdown.
+//
+// It replaces the following pattern:
+//
+//	select {
+//	case <-time.After(d):
+//	case <-shutdown.C:
+//	}
+func Sleep(d time.Duration) bool { return std.Sleep(d) }
+
+// Sleep pauses the current goroutine for at least the duration d,
+// or until shutdown is initiated. See the package-level Sleep.
+func (m *Manager) Sleep(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-m.C:
+		return false
+	}
+}
+
+// Timer is like time.Timer, but it also fires when shutdown is initiated.
+// Use NewTimer to create one.
+type Timer struct {
+	// C receives the current time when the timer expires
+	// or when shutdown is initiated, whichever happens first.
+	C <-chan time.Time
+
+	m      *Manager
+	c      chan time.Time
+	t      *time.Timer
+	stopCh chan struct{}
+
+	mu   sync.Mutex
+	done bool // done tells if the timer has fired or been stopped
+}
+
+// NewTimer creates a new Timer that will send the current time on its channel
+// after at least duration d, or when shutdown is initiated.
+func NewTimer(d time.Duration) *Timer { return std.NewTimer(d) }
+
+// NewTimer creates a new Timer that fires after at least duration d,
+// or when shutdown is initiated. See the package-level NewTimer.
+func (m *Manager) NewTimer(d time.Duration) *Timer {
+	c := make(chan time.Time, 1)
+	t := &Timer{
+		C:      c,
+		m:      m,
+		c:      c,
+		t:      time.NewTimer(d),
+		stopCh: make(chan struct{}),
+	}
+
+	go func() {
+		select {
+		case now := <-t.t.C:
+			if t.take() {
+				c <- now
+			}
+		case <-m.C:
+			t.t.Stop()
+			if t.take() {
+				c <- time.Now()
+			}
+		case <-t.stopCh:
+		}
+	}()
+
+	return t
+}
+
+// take marks the timer done, and returns true if it was not done before.
+// Whether the timer fires or is stopped is decided by whoever takes it first.
+func (t *Timer) take() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.done {
+		return false
+	}
+	t.done = true
+	return true
+}
+
+// Stop prevents the Timer from firing. It returns true if the call stops
+// the timer, false if the timer has already fired (expired or shutdown
+// initiated) or been stopped.
+//
+// If Stop returns false because the timer has fired, a value is (or will be)
+// sent on C, so the usual idiom of draining C works:
+//
+//	if !t.Stop() {
+//		<-t.C
+//	}
+//
+// (unless the timer has been stopped before, or C has already been received from).
+func (t *Timer) Stop() bool {
+	if !t.take() {
+		return false
+	}
+	if t.m.Initiated() {
+		// Shutdown fired the timer, but the goroutine has not yet observed it.
+		t.c <- time.Now()
+		return false
+	}
+	t.t.Stop()
+	close(t.stopCh)
+	return true
+}
+
+// WithRunPendingFuncs makes AfterFunc callbacks still pending when shutdown is
+// initiated run immediately. By default they are cancelled.
+func WithRunPendingFuncs() Option {
+	return func(m *Manager) {
+		m.runPendingFuncs = true
+	}
+}
+
+// FuncTimer is a timer created by AfterFunc.
+type FuncTimer struct {
+	t      *time.Timer
+	stopCh chan struct{}
+
+	mu   sync.Mutex
+	done bool // done tells if the callback has been run, cancelled or stopped
+}
+
+// AfterFunc waits for the duration d to elapse and then calls f in its own
+// goroutine, like time.AfterFunc, but it also takes part in the shutdown:
+//
+//   - the final Wait waits for the pending callback (until it is run,
+//     cancelled or stopped);
+//   - when shutdown is initiated before d elapses, the callback is cancelled,
+//     or run immediately if enabled with WithRunPendingFuncs.
+//
+// This prevents stray timers firing after cleanup hooks have already run.
+func AfterFunc(d time.Duration, f func()) *FuncTimer { return std.AfterFunc(d, f) }
+
+// AfterFunc calls f in its own goroutine after the duration d, or cancels it
+// (or calls it immediately) on shutdown. See the package-level AfterFunc.
+func (m *Manager) AfterFunc(d time.Duration, f func()) *FuncTimer {
+	t := &FuncTimer{
+		t:      time.NewTimer(d),
+		stopCh: make(chan struct{}),
+	}
+
+	m.Go(func() {
+		select {
+		case <-t.t.C:
+			if t.take() {
+				f()
+			}
+		case <-m.C:
+			t.t.Stop()
+			if t.take() && m.runPendingFuncs {
+				f()
+			}
+		case <-t.stopCh:
+		}
+	})
+
+	return t
+}
+
+// take marks the timer done, and returns true if it was not done before.
+func (t *FuncTimer) take() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.done {
+		return false
+	}
+	t.done = true
+	return true
+}
+
+// Stop prevents the callback from being called. It returns true if the call
+// stops the timer, false if the callback has already been run (or is running),
+// has been cancelled by shutdown or has been stopped.
+func (t *FuncTimer) Stop() bool {
+	if !t.take() {
+		return false
+	}
+	t.t.Stop()
+	close(t.stopCh)
+	return true
+}
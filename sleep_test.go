@@ -0,0 +1,142 @@
+package shutdown
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSleep(t *testing.T) {
+	m := newTestManager()
+	if !m.Sleep(time.Millisecond) {
+		t.Error("Sleep returned false without shutdown")
+	}
+
+	time.AfterFunc(20*time.Millisecond, m.InitiateManual)
+	start := time.Now()
+	if m.Sleep(time.Hour) {
+		t.Error("Sleep returned true on shutdown")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Sleep did not wake on shutdown (took %v)", d)
+	}
+
+	// After initiation, Sleep returns immediately.
+	if m.Sleep(time.Hour) {
+		t.Error("Sleep returned true after initiation")
+	}
+	m.Wait()
+}
+
+func TestTimer(t *testing.T) {
+	m := newTestManager()
+
+	tm := m.NewTimer(time.Millisecond)
+	<-tm.C
+	if tm.Stop() {
+		t.Error("Stop returned true for a fired timer")
+	}
+
+	tm = m.NewTimer(time.Hour)
+	if !tm.Stop() {
+		t.Error("Stop returned false for a pending timer")
+	}
+	if tm.Stop() {
+		t.Error("second Stop returned true")
+	}
+
+	tm = m.NewTimer(time.Hour)
+	stopped := m.NewTimer(time.Hour)
+	stopped.Stop()
+	m.InitiateManual()
+	select {
+	case <-tm.C:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timer did not fire on shutdown")
+	}
+	if tm.Stop() {
+		t.Error("Stop returned true for a timer fired by shutdown")
+	}
+	select {
+	case <-stopped.C:
+		t.Error("stopped timer fired on shutdown")
+	case <-time.After(20 * time.Millisecond):
+	}
+	m.Wait()
+}
+
+func TestTimerStopAfterInitiation(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		m := newTestManager()
+		tm := m.NewTimer(time.Hour)
+		m.InitiateManual()
+		<-m.C
+		if tm.Stop() {
+			t.Fatal("Stop returned true after initiation")
+		}
+		// Stop returned false: a value must be sent on C.
+		select {
+		case <-tm.C:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Stop returned false, but C never received")
+		}
+		m.Wait()
+	}
+}
+
+func TestAfterFunc(t *testing.T) {
+	m := newTestManager()
+
+	fired := make(chan struct{})
+	ft := m.AfterFunc(time.Millisecond, func() { close(fired) })
+	<-fired
+	if ft.Stop() {
+		t.Error("Stop returned true for a run callback")
+	}
+
+	ft = m.AfterFunc(time.Hour, func() { t.Error("stopped callback run") })
+	if !ft.Stop() {
+		t.Error("Stop returned false for a pending callback")
+	}
+	if ft.Stop() {
+		t.Error("second Stop returned true")
+	}
+
+	// Pending callbacks are cancelled on shutdown, and the final Wait doesn't
+	// wait for them.
+	ft = m.AfterFunc(time.Hour, func() { t.Error("pending callback run on shutdown") })
+	m.Close()
+	if ft.Stop() {
+		t.Error("Stop returned true for a callback cancelled by shutdown")
+	}
+}
+
+func TestAfterFuncWaited(t *testing.T) {
+	m := newTestManager()
+	done := false
+	m.AfterFunc(10*time.Millisecond, func() {
+		time.Sleep(20 * time.Millisecond)
+		done = true
+	})
+	time.Sleep(15 * time.Millisecond)
+
+	// The final Wait waits for a running callback.
+	m.Close()
+	if !done {
+		t.Error("final Wait returned before the running callback")
+	}
+}
+
+func TestAfterFuncRunPending(t *testing.T) {
+	m := newTestManager(WithRunPendingFuncs())
+	ran := false
+	ft := m.AfterFunc(time.Hour, func() { ran = true })
+
+	// Pending callbacks are run on shutdown, before the final Wait returns.
+	m.Close()
+	if !ran {
+		t.Error("pending callback not run on shutdown")
+	}
+	if ft.Stop() {
+		t.Error("Stop returned true for a callback run by shutdown")
+	}
+}
@@ -0,0 +1,215 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// Source is a source that may initiate a shutdown, such as OS signals,
+// a test injector or an admin API.
+type Source interface {
+	// Wait blocks until the source wants to initiate a shutdown,
+	// in which case it returns true, or until ctx is done,
+	// in which case it returns false.
+	//
+	// The passed ctx is done when shutdown has been initiated (by any source).
+	Wait(ctx context.Context) bool
+}
+
+// SourceFunc is an adapter to allow the use of ordinary functions as Sources.
+type SourceFunc func(ctx context.Context) bool
+
+// Wait calls f(ctx).
+func (f SourceFunc) Wait(ctx context.Context) bool {
+	return f(ctx)
+}
+
+// AddSource adds a source which may initiate a shutdown.
+// src.Wait is called in a new goroutine.
+//
+// If src implements fmt.Stringer, its String method is called after Wait
+// returns true to describe the cause of the initiation (see Causes).
+func AddSource(src Source) { std.AddSource(src) }
+
+// AddSource adds a source which may initiate a shutdown. See the package-level AddSource.
+func (m *Manager) AddSource(src Source) {
+	go func() {
+		if !src.Wait(m.Context) {
+			return
+		}
+		cause := "source"
+		if s, ok := src.(fmt.Stringer); ok {
+			cause = s.String()
+		}
+		if m.startInitiation(cause) {
+			c := &Cause{Text: cause}
+			if s, ok := src.(interface {
+				received() (os.Signal, time.Time)
+			}); ok {
+				var at time.Time
+				c.Signal, at = s.received()
+				m.mu.Lock()
+				m.signalAt = at
+				m.mu.Unlock()
+			} else {
+				// Signal sources log the received signal themselves.
+				m.noticef("Shutdown initiated: %s...", cause)
+			}
+			if s, ok := src.(interface{ causeErr() error }); ok {
+				c.Err = s.causeErr()
+			}
+			m.broadcast(c)
+		}
+	}()
+}
+
+// BindContext binds ctx to the shutdown: when ctx is cancelled (e.g. the run
+// context of an orchestrator SDK), a shutdown is initiated. The cause of the
+// initiation is "context: " followed by the cause of the cancellation
+// (see context.Cause), e.g. "context: context canceled". The cause of the
+// cancellation is also preserved as the Err of the Cause of Context, so e.g.
+// errors.Is(context.Cause(shutdown.Context), err) reports whether ctx was
+// cancelled with err.
+func BindContext(ctx context.Context) { std.BindContext(ctx) }
+
+// BindContext binds ctx to the shutdown. See the package-level BindContext.
+func (m *Manager) BindContext(ctx context.Context) {
+	m.AddSource(contextSource{ctx: ctx})
+}
+
+// contextSource is a Source initiating shutdown when ctx is done.
+type contextSource struct {
+	ctx context.Context
+}
+
+// Wait implements Source.
+func (s contextSource) Wait(ctx context.Context) bool {
+	select {
+	case <-s.ctx.Done():
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// String returns the cause of the initiation, e.g. "context: context canceled".
+func (s contextSource) String() string {
+	return fmt.Sprintf("context: %v", context.Cause(s.ctx))
+}
+
+// causeErr returns the cause of the cancellation of ctx.
+func (s contextSource) causeErr() error {
+	return context.Cause(s.ctx)
+}
+
+// SignalSource returns a Source that initiates a shutdown when any of the
+// given signals is received. Signals are subscribed to immediately.
+// As with signal.Notify, if no signals are provided, all incoming signals
+// are relayed.
+func SignalSource(sigs ...os.Signal) Source {
+	return newSignalSource(nil, sigs...)
+}
+
+// signalSource is a Source initiating shutdown when a signal is received on ch.
+type signalSource struct {
+	// ch is a signal channel used to receive signals.
+	// Buffered to make sure we don't miss it (send on it is non-blocking).
+	ch chan os.Signal
+
+	// sig is the received signal.
+	sig os.Signal
+
+	// at is the time when sig was received (or confirmed, see confirm).
+	at time.Time
+
+	// m is the manager whose logger is used, nil means the default.
+	m *Manager
+
+	// stop is closed to unsubscribe (see unsubscribe).
+	stop chan struct{}
+
+	// confirm is called with a received signal, if not nil. If it returns false,
+	// the signal is ignored. ctx is the ctx passed to Wait, more delivers
+	// subsequent signals.
+	confirm func(ctx context.Context, sig os.Signal, more <-chan os.Signal) bool
+
+	// onSignal is called with the signal initiating shutdown, if not nil.
+	onSignal func(sig os.Signal)
+
+	// force is called with signals received after shutdown has been initiated.
+	// If nil, signals are unsubscribed from when Wait returns.
+	force func(sig os.Signal)
+}
+
+// newSignalSource creates a new signalSource, subscribed to the given signals.
+// m is the manager whose logger is used, nil means the default.
+func newSignalSource(m *Manager, sigs ...os.Signal) *signalSource {
+	s := &signalSource{ch: make(chan os.Signal, 1), m: m, stop: make(chan struct{})}
+	signal.Notify(s.ch, sigs...)
+	return s
+}
+
+// Wait implements Source.
+func (s *signalSource) Wait(ctx context.Context) bool {
+	if s.force == nil {
+		defer signal.Stop(s.ch)
+	} else {
+		defer func() { go s.awaitForce() }()
+	}
+
+	for {
+		select {
+		case s.sig = <-s.ch:
+			if s.confirm != nil && !s.confirm(ctx, s.sig, s.ch) {
+				continue
+			}
+			s.at = time.Now()
+			m := s.m
+			if m == nil {
+				m = std
+			}
+			if s.onSignal != nil {
+				s.onSignal(s.sig)
+			}
+			m.noticef("Received '%v' signal, broadcasting shutdown...", s.sig)
+			return true
+		case <-ctx.Done():
+			return false
+		case <-s.stop:
+			return false
+		}
+	}
+}
+
+// awaitForce calls force with signals received after Wait returned,
+// until unsubscribed.
+func (s *signalSource) awaitForce() {
+	defer signal.Stop(s.ch)
+
+	for {
+		select {
+		case sig := <-s.ch:
+			s.force(sig)
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// unsubscribe makes Wait return false, and unsubscribes from the signals.
+func (s *signalSource) unsubscribe() {
+	close(s.stop)
+}
+
+// received returns the received signal, and the time it was received.
+func (s *signalSource) received() (os.Signal, time.Time) {
+	return s.sig, s.at
+}
+
+// String returns the cause of the initiation, e.g. "signal: terminated".
+func (s *signalSource) String() string {
+	return fmt.Sprintf("signal: %v", s.sig)
+}
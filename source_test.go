@@ -0,0 +1,100 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBindContext(t *testing.T) {
+	m := newTestManager()
+	myErr := errors.New("lease lost")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	m.BindContext(ctx)
+
+	cancel(myErr)
+	<-m.C
+
+	cause := context.Cause(m.Context)
+	if !errors.Is(cause, myErr) {
+		t.Errorf("cause of Context is %v, want it to wrap %v", cause, myErr)
+	}
+	if !errors.Is(cause, ErrInitiated) {
+		t.Errorf("cause of Context is %v, want it to match %v", cause, ErrInitiated)
+	}
+	if got, want := m.Reason(), "context: lease lost"; got != want {
+		t.Errorf("Reason is %q, want %q", got, want)
+	}
+	m.Close()
+}
+
+func TestSourceFunc(t *testing.T) {
+	m := newTestManager()
+	fire := make(chan struct{})
+	m.AddSource(SourceFunc(func(ctx context.Context) bool {
+		select {
+		case <-fire:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}))
+
+	close(fire)
+	<-m.C
+	if r := m.Reason(); r != "source" {
+		t.Errorf("Reason is %q, want %q", r, "source")
+	}
+	m.Wait()
+}
+
+// namedSource is a Source describing its cause, initiating when fire is closed.
+type namedSource struct {
+	fire chan struct{}
+}
+
+func (s namedSource) Wait(ctx context.Context) bool {
+	select {
+	case <-s.fire:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s namedSource) String() string { return "admin API" }
+
+func TestSourceStringer(t *testing.T) {
+	m := newTestManager()
+	src := namedSource{fire: make(chan struct{})}
+	m.AddSource(src)
+
+	close(src.fire)
+	<-m.C
+	if r := m.Reason(); r != "admin API" {
+		t.Errorf("Reason is %q, want %q", r, "admin API")
+	}
+	m.Wait()
+}
+
+func TestSourceWaitCancelled(t *testing.T) {
+	m := newTestManager()
+	result := make(chan bool, 1)
+	m.AddSource(SourceFunc(func(ctx context.Context) bool {
+		<-ctx.Done()
+		result <- false
+		return false
+	}))
+
+	m.InitiateManual() // Another source initiates first.
+	select {
+	case <-result:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait of the source not cancelled when another source initiated")
+	}
+	if causes := m.Causes(); len(causes) != 1 || causes[0] != "manual" {
+		t.Errorf("Causes are %v, want [manual]", causes)
+	}
+	m.Wait()
+}
@@ -0,0 +1,28 @@
+//go:build unix
+
+package shutdown
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSignalSource(t *testing.T) {
+	m := newTestManager()
+	m.AddSource(SignalSource(syscall.SIGUSR1))
+
+	raise(syscall.SIGUSR1)
+	select {
+	case <-m.C:
+	case <-time.After(5 * time.Second):
+		t.Fatal("signal did not initiate shutdown")
+	}
+	if r := m.Reason(); r != "signal: user defined signal 1" {
+		t.Errorf("Reason is %q, want %q", r, "signal: user defined signal 1")
+	}
+	if sig := m.Signal(); sig != syscall.SIGUSR1 {
+		t.Errorf("Signal is %v, want %v", sig, syscall.SIGUSR1)
+	}
+	m.Wait()
+}
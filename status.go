@@ -0,0 +1,252 @@
+package shutdown
+
+import (
+	"fmt"
+	"time"
+)
+
+// StatusVersion is the version of the Status format. It is incremented
+// on incompatible changes.
+const StatusVersion = 1
+
+// State is the lifecycle state of an app regarding shutdown.
+//
+// Values and their names are stable, so they can be embedded in proto and
+// JSON messages (e.g. health responses), new states are only appended.
+// State is marshaled as text using the proto enum style names
+// (e.g. "STATE_RUNNING").
+type State int32
+
+// States.
+const (
+	// StateUnspecified is the zero value, not a valid state.
+	StateUnspecified State = 0
+
+	// StateRunning means shutdown has not been initiated.
+	StateRunning State = 1
+
+	// StateShuttingDown means shutdown has been initiated (see Reason), but
+	// the final Wait has not yet returned.
+	StateShuttingDown State = 2
+
+	// StateCompleted means the final Wait has returned.
+	StateCompleted State = 3
+)
+
+// stateNames holds the names of the states.
+var stateNames = map[State]string{
+	StateUnspecified:  "STATE_UNSPECIFIED",
+	StateRunning:      "STATE_RUNNING",
+	StateShuttingDown: "STATE_SHUTTING_DOWN",
+	StateCompleted:    "STATE_COMPLETED",
+}
+
+// String returns the name of the state.
+func (s State) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("State(%d)", int32(s))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *State) UnmarshalText(text []byte) error {
+	for st, name := range stateNames {
+		if name == string(text) {
+			*s = st
+			return nil
+		}
+	}
+	return fmt.Errorf("shutdown: unknown state %q", text)
+}
+
+// Status is a snapshot of the shutdown status, meant to be embedded in
+// status APIs. Field names follow proto conventions.
+//
+// A Status is built in one pass under the lock of the Manager, so its fields
+// are consistent with each other, and it is safe to marshal from any goroutine.
+type Status struct {
+	// Version is the version of the format, see StatusVersion.
+	Version int `json:"version"`
+
+	// State is the lifecycle state.
+	State State `json:"state"`
+
+	// Reason is the cause of the initiation that initiated the shutdown,
+	// see Reason.
+	Reason string `json:"reason,omitempty"`
+
+	// Causes are the causes of initiation attempts, see Causes.
+	Causes []string `json:"causes,omitempty"`
+
+	// StartedAt is the time when shutdown was initiated in RFC 3339 format
+	// (as proto Timestamps in JSON), see StartedAt.
+	StartedAt string `json:"started_at,omitempty"`
+
+	// UptimeSeconds is the time elapsed since the app started, see Uptime.
+	UptimeSeconds float64 `json:"uptime_seconds"`
+
+	// ShutdownSeconds is the time elapsed since shutdown was initiated,
+	// see Duration.
+	ShutdownSeconds float64 `json:"shutdown_seconds,omitempty"`
+
+	// Signal is the name of the signal that initiated the shutdown, see Signal.
+	Signal string `json:"signal,omitempty"`
+
+	// Error is the error passed to InitiateError, see Err.
+	Error string `json:"error,omitempty"`
+
+	// HookErrors are the errors of shutdown hooks, see Errors.
+	HookErrors []string `json:"hook_errors,omitempty"`
+
+	// Phase is the phase being executed (or the last executed phase once
+	// all hooks have completed), empty if hooks have not started.
+	Phase Phase `json:"phase,omitempty"`
+
+	// HooksCompleted is the number of completed hooks (of all phases).
+	HooksCompleted int `json:"hooks_completed,omitempty"`
+
+	// HooksTotal is the number of hooks to run (of all phases), 0 if hooks
+	// have not started.
+	HooksTotal int `json:"hooks_total,omitempty"`
+
+	// Hooks are the statuses of the hooks to run, in run order (hooks of a
+	// phase registered with OnPhase first), empty if hooks have not started.
+	Hooks []HookStatus `json:"hooks,omitempty"`
+
+	// RunningTasks is the number of running goroutines started by Go and of
+	// units of work admitted by Gates.
+	RunningTasks int `json:"running_tasks,omitempty"`
+
+	// PendingTasks holds the number of running goroutines started by Go by
+	// their names: the package-qualified function and the location of the
+	// Go call, see DrainTime. Units of work admitted by Gates are not named.
+	PendingTasks map[string]int `json:"pending_tasks,omitempty"`
+
+	// SignalToCancelSeconds is the latency between receiving the signal and
+	// cancelling Context, see Latencies.
+	SignalToCancelSeconds float64 `json:"signal_to_cancel_seconds,omitempty"`
+
+	// CancelToFirstHookSeconds is the latency between cancelling Context and
+	// the start of the first hook, see Latencies.
+	CancelToFirstHookSeconds float64 `json:"cancel_to_first_hook_seconds,omitempty"`
+
+	// Drain is the distribution of the drain times of tasks, see DrainTimes.
+	Drain *DrainStats `json:"drain,omitempty"`
+
+	// HookDrain is the distribution of the run times of hooks, see DrainTimes.
+	HookDrain *DrainStats `json:"hook_drain,omitempty"`
+}
+
+// HookState is the state of a shutdown hook, see HookStatus.
+type HookState string
+
+// Hook states.
+const (
+	// HookPending means the hook has not started.
+	HookPending HookState = "pending"
+
+	// HookRunning means the hook is running.
+	HookRunning HookState = "running"
+
+	// HookDone means the hook has completed successfully.
+	HookDone HookState = "done"
+
+	// HookFailed means the hook has failed: it returned an error, panicked,
+	// timed out, or was skipped or abandoned due to escalation.
+	HookFailed HookState = "failed"
+)
+
+// HookStatus is the status of a shutdown hook, see Status.
+type HookStatus struct {
+	// Name is the name of the hook (see WithName).
+	Name string `json:"name"`
+
+	// Phase is the phase the hook is run in.
+	Phase Phase `json:"phase"`
+
+	// State is the state of the hook.
+	State HookState `json:"state"`
+
+	// DurationSeconds is the time the hook took, if it has completed.
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+
+	// Error is the error of the hook, if it has failed.
+	Error string `json:"error,omitempty"`
+
+	// Stack is the stack trace of the panic of the hook, if it has panicked.
+	Stack string `json:"stack,omitempty"`
+}
+
+// CurrentStatus returns the current shutdown status.
+func CurrentStatus() Status { return std.Status() }
+
+// Status returns the current shutdown status.
+func (m *Manager) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st := Status{
+		Version:        StatusVersion,
+		State:          m.stateLocked(),
+		UptimeSeconds:  Uptime().Seconds(),
+		Phase:          m.phase,
+		HooksCompleted: m.hooksCompleted,
+		HooksTotal:     m.hooksTotal,
+		Hooks:          append([]HookStatus(nil), m.hookStatuses...),
+		RunningTasks:   m.tasks,
+	}
+	if len(m.causes) > 0 {
+		st.Reason = m.causes[0]
+		st.Causes = append([]string(nil), m.causes...)
+	}
+	if !m.initiatedAt.IsZero() {
+		st.StartedAt = m.initiatedAt.UTC().Format(time.RFC3339Nano)
+		st.ShutdownSeconds = time.Since(m.initiatedAt).Seconds()
+	}
+	if m.signal != nil {
+		st.Signal = m.signal.String()
+	}
+	if m.err != nil {
+		st.Error = m.err.Error()
+	}
+	for _, err := range m.hookErrors {
+		st.HookErrors = append(st.HookErrors, err.Error())
+	}
+	if len(m.taskSites) > 0 {
+		st.PendingTasks = make(map[string]int, len(m.taskSites))
+		for site, n := range m.taskSites {
+			st.PendingTasks[site] = n
+		}
+	}
+	l := m.latencies()
+	st.SignalToCancelSeconds = l.SignalToCancel.Seconds()
+	st.CancelToFirstHookSeconds = l.CancelToFirstHook.Seconds()
+	dts := m.sortedDrainTimes()
+	st.Drain = newDrainStats(dts, false)
+	st.HookDrain = newDrainStats(dts, true)
+	return st
+}
+
+// state returns the lifecycle state.
+func (m *Manager) state() State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stateLocked()
+}
+
+// stateLocked returns the lifecycle state. m.mu must be held.
+func (m *Manager) stateLocked() State {
+	switch {
+	case m.completed:
+		return StateCompleted
+	case len(m.causes) > 0:
+		return StateShuttingDown
+	}
+	return StateRunning
+}
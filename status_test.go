@@ -0,0 +1,120 @@
+package shutdown
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusProgress(t *testing.T) {
+	m := newTestManager()
+	m.Go(func() { <-m.C })
+
+	st := m.Status()
+	if st.State != StateRunning || st.Phase != "" || st.HooksTotal != 0 || st.RunningTasks != 1 {
+		t.Errorf("Status before initiation is %+v, want running with 1 task and no phase", st)
+	}
+	if st.Reason != "" || st.StartedAt != "" || len(st.Hooks) != 0 {
+		t.Errorf("Status before initiation is %+v, want no reason, start time and hooks", st)
+	}
+	if len(st.PendingTasks) != 1 {
+		t.Fatalf("pending tasks are %v, want 1", st.PendingTasks)
+	}
+	for site, n := range st.PendingTasks {
+		if !strings.HasPrefix(site, "github.com/icza/shutdown.TestStatusProgress (status_test.go:") || n != 1 {
+			t.Errorf("pending tasks are %v, want 1 started in TestStatusProgress", st.PendingTasks)
+		}
+	}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	m.OnPhase(PhaseDrain, func() {}, WithName("intake"))
+	m.OnShutdown(func() {
+		close(started)
+		<-release
+	}, WithName("db"))
+	m.OnShutdownError(func() error { return errors.New("flush failed") }, WithName("cache"))
+	m.InitiateManual()
+	<-started
+
+	st = m.Status()
+	if st.State != StateShuttingDown || st.Phase != PhaseStop || st.HooksCompleted != 1 || st.HooksTotal != 3 {
+		t.Errorf("Status during stop phase is %+v, want stop phase with 1/3 hooks completed", st)
+	}
+	if st.Reason != "manual" || st.StartedAt != m.StartedAt().UTC().Format(time.RFC3339Nano) {
+		t.Errorf("Status during stop phase has reason %q, started at %q, want manual at %v", st.Reason, st.StartedAt, m.StartedAt())
+	}
+	var states []HookState
+	for _, hs := range st.Hooks {
+		states = append(states, hs.State)
+	}
+	if want := []HookState{HookDone, HookRunning, HookPending}; !reflect.DeepEqual(states, want) {
+		t.Errorf("hook states are %v, want %v", states, want)
+	}
+
+	close(release)
+	m.Wait()
+
+	st = m.Status()
+	if st.State != StateCompleted || st.HooksCompleted != 3 || st.HooksTotal != 3 || st.RunningTasks != 0 || st.PendingTasks != nil {
+		t.Errorf("Status after Wait is %+v, want 3/3 hooks completed and no tasks", st)
+	}
+	want := []HookStatus{
+		{Name: "intake", Phase: PhaseDrain, State: HookDone},
+		{Name: "db", Phase: PhaseStop, State: HookDone},
+		{Name: "cache", Phase: PhaseStop, State: HookFailed, Error: "flush failed"},
+	}
+	for i := range st.Hooks {
+		st.Hooks[i].DurationSeconds = 0
+	}
+	if !reflect.DeepEqual(st.Hooks, want) {
+		t.Errorf("hook statuses are %+v, want %+v", st.Hooks, want)
+	}
+}
+
+func TestStatusConsistent(t *testing.T) {
+	// State and Reason / Causes must agree in every snapshot.
+	m := newTestManager()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			st := m.Status()
+			if (st.State == StateRunning) != (st.Reason == "") || (st.Reason == "") != (len(st.Causes) == 0) {
+				t.Errorf("inconsistent Status: state %v, reason %q, causes %v", st.State, st.Reason, st.Causes)
+				return
+			}
+			if st.State == StateCompleted {
+				return
+			}
+		}
+	}()
+	m.InitiateManual()
+	m.Wait()
+	<-done
+}
+
+func TestStateText(t *testing.T) {
+	for st := range stateNames {
+		text, err := st.MarshalText()
+		if err != nil {
+			t.Errorf("MarshalText of %d failed: %v", st, err)
+		}
+		var got State
+		if err := got.UnmarshalText(text); err != nil || got != st {
+			t.Errorf("UnmarshalText(%q) = %v, %v, want %v", text, got, err, st)
+		}
+	}
+	if text, _ := StateShuttingDown.MarshalText(); string(text) != "STATE_SHUTTING_DOWN" {
+		t.Errorf("MarshalText of StateShuttingDown is %q, want %q", text, "STATE_SHUTTING_DOWN")
+	}
+
+	var st State
+	if err := st.UnmarshalText([]byte("STATE_EXPLODED")); err == nil {
+		t.Error("UnmarshalText of an unknown state succeeded")
+	}
+	if s := State(42).String(); s != "State(42)" {
+		t.Errorf("String of an unknown state is %q, want %q", s, "State(42)")
+	}
+}
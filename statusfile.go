@@ -0,0 +1,72 @@
+package shutdown
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WithStatusFile makes the manager keep the file at path (e.g. "/run/app.status")
+// updated with a short description of the current lifecycle state, e.g.
+// "running" or "shutting down: stop phase, 3/7 hooks done", so tools like
+// simple scripts can observe the shutdown progress without an HTTP endpoint.
+//
+// The file is replaced atomically on each update (a temporary file is written
+// in the same directory, then renamed).
+func WithStatusFile(path string) Option {
+	return func(m *Manager) {
+		m.statusFile = path
+	}
+}
+
+// statusText returns the description of the current lifecycle state.
+func (m *Manager) statusText() string {
+	switch m.state() {
+	case StateCompleted:
+		return "stopped"
+	case StateShuttingDown:
+		m.mu.Lock()
+		phase, done, total := m.phase, m.hooksCompleted, m.hooksTotal
+		m.mu.Unlock()
+		if phase == "" {
+			return "shutting down"
+		}
+		return fmt.Sprintf("shutting down: %s phase, %d/%d hooks done", phase, done, total)
+	}
+	return "running"
+}
+
+// updateStatusFile updates the status file, if any (see WithStatusFile).
+func (m *Manager) updateStatusFile() {
+	path := m.statusFile
+	if path == "" {
+		return
+	}
+
+	m.statusFileMu.Lock()
+	defer m.statusFileMu.Unlock()
+
+	if err := writeFileAtomic(path, []byte(m.statusText()+"\n")); err != nil {
+		m.errorf("Failed to update status file: %v", err)
+	}
+}
+
+// writeFileAtomic writes data to a temporary file in the directory of path,
+// then renames it to path.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
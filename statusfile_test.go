@@ -0,0 +1,50 @@
+package shutdown
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStatusFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.status")
+	m := newTestManager(WithStatusFile(path))
+
+	read := func() string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(data))
+	}
+
+	if got := read(); got != "running" {
+		t.Errorf("status is %q, want %q", got, "running")
+	}
+
+	var inStop string
+	m.OnShutdown(func() { inStop = read() })
+	m.OnShutdown(func() {})
+	m.Close()
+
+	if want := "shutting down: stop phase, 0/2 hooks done"; inStop != want {
+		t.Errorf("status in the first hook is %q, want %q", inStop, want)
+	}
+	if got := read(); got != "stopped" {
+		t.Errorf("status is %q, want %q", got, "stopped")
+	}
+
+	// No temporary files are left behind.
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("%d files in the status directory, want 1", len(entries))
+	}
+}
+
+func TestStatusFileError(t *testing.T) {
+	// Updates failing (e.g. missing directory) are only logged.
+	m := newTestManager(WithStatusFile(filepath.Join(t.TempDir(), "missing", "app.status")))
+	m.Close()
+}
@@ -0,0 +1,114 @@
+package shutdown
+
+import "time"
+
+const (
+	// clockTick is the interval of the clock observations detecting suspension.
+	clockTick = time.Second
+
+	// clockSlack is the tolerated delay of a clock observation: a gap between
+	// observations longer than clockTick+clockSlack is considered a suspension.
+	clockSlack = 2 * time.Second
+)
+
+// WithSuspendAwareness makes the shutdown deadlines (the grace timeout and the
+// watchdog, but not the hard kill timer, see WithHardKill) not count the time
+// the system was suspended (e.g. a laptop put to sleep in the middle of a
+// drain), avoiding spurious forced exits of desktop and agent software.
+//
+// Suspension is detected by observing the wall clock every second during
+// shutdown: a large jump ahead is considered a suspension, and it is logged
+// even without this option. Note that a wall clock adjustment (e.g. by NTP)
+// may also be taken for a suspension.
+func WithSuspendAwareness() Option {
+	return func(m *Manager) {
+		m.suspendAware = true
+	}
+}
+
+// deadline returns a channel that is closed when d has elapsed since the
+// shutdown initiation (not counting suspensions if suspend awareness is
+// enabled, see WithSuspendAwareness). All deadlines share this single anchor
+// (m.initiatedAt, see StartedAt), measured on the monotonic clock unless
+// suspend awareness is enabled. The returned channel is never closed
+// once the final Wait has returned.
+func (m *Manager) deadline(d time.Duration) <-chan struct{} {
+	m.clockOnce.Do(func() { go m.watchClock() })
+
+	c := make(chan struct{})
+	go func() {
+		for {
+			m.observeClock()
+			remaining := d - m.awake()
+			if remaining <= 0 {
+				break
+			}
+
+			t := time.NewTimer(remaining)
+			select {
+			case <-t.C:
+			case <-m.waitDone:
+				t.Stop()
+				return
+			}
+		}
+		close(c)
+	}()
+	return c
+}
+
+// watchClock observes the clock every clockTick until the final Wait returns.
+func (m *Manager) watchClock() {
+	ticker := time.NewTicker(clockTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.observeClock()
+		case <-m.waitDone:
+			return
+		}
+	}
+}
+
+// observeClock observes the wall clock, and records (and logs) a suspension
+// if the clock jumped ahead since the previous observation.
+func (m *Manager) observeClock() {
+	now := time.Now().Round(0) // Strip the monotonic reading to use the wall clock.
+
+	m.mu.Lock()
+	last := m.lastClockObs
+	if last.IsZero() {
+		last = m.initiatedAt.Round(0)
+	}
+	m.lastClockObs = now
+	gap := now.Sub(last)
+	suspended := gap > clockTick+clockSlack
+	if suspended {
+		m.suspended += gap - clockTick
+	}
+	aware := m.suspendAware
+	m.mu.Unlock()
+
+	if suspended {
+		if aware {
+			m.warnf("Clock jumped %v ahead during shutdown (system suspended?), extending deadlines.", gap)
+		} else {
+			m.warnf("Clock jumped %v ahead during shutdown (system suspended?).", gap)
+		}
+	}
+}
+
+// awake returns the time elapsed since the shutdown initiation, not counting
+// suspensions if suspend awareness is enabled.
+func (m *Manager) awake() time.Duration {
+	if !m.suspendAware {
+		return m.Duration()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return time.Now().Round(0).Sub(m.initiatedAt.Round(0)) - m.suspended
+}
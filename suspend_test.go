@@ -0,0 +1,59 @@
+package shutdown
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// simulateSuspension makes m look like shutdown was initiated 10 seconds
+// ago, and the system was suspended since then.
+func simulateSuspension(m *Manager) {
+	m.mu.Lock()
+	m.initiatedAt = time.Now().Add(-10 * time.Second)
+	m.lastClockObs = m.initiatedAt.Round(0)
+	m.mu.Unlock()
+}
+
+func TestDeadlineSuspended(t *testing.T) {
+	for _, aware := range []bool{false, true} {
+		var opts []Option
+		if aware {
+			opts = append(opts, WithSuspendAwareness())
+		}
+		m := newTestManager(opts...)
+		simulateSuspension(m)
+
+		var fired bool
+		select {
+		case <-m.deadline(5 * time.Second):
+			fired = true
+		case <-time.After(100 * time.Millisecond):
+		}
+		// Without awareness the deadline has passed, with awareness
+		// the suspended 9 seconds are not counted.
+		if fired == aware {
+			t.Errorf("[aware: %t] deadline fired: %t", aware, fired)
+		}
+
+		m.mu.Lock()
+		suspended := m.suspended
+		m.mu.Unlock()
+		if suspended < 8*time.Second {
+			t.Errorf("[aware: %t] suspended is %v, want about 9s", aware, suspended)
+		}
+		close(m.waitDone) // Stop watching the clock.
+	}
+}
+
+func TestSuspensionLogged(t *testing.T) {
+	// Suspension is a warning, so it is logged even by the Prod profile.
+	l := &logRecorder{}
+	m := newTestManager(WithLogger(quietLogger{l}))
+	simulateSuspension(m)
+
+	m.observeClock()
+	if got := l.String(); !strings.HasPrefix(got, "Clock jumped") {
+		t.Errorf("logged %q, want the suspension", got)
+	}
+}
@@ -0,0 +1,87 @@
+package shutdown
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Validate checks the registered hooks and the configured timeouts for
+// impossible combinations, and reports them, e.g.:
+//   - the sum of the budgets of the sequentially executed phases exceeding the
+//     grace timeout (see WithGraceTimeout),
+//   - a hook with a timeout longer than the grace timeout,
+//   - the watchdog or the hard kill timer firing before the grace timeout expires
+//     (see WithWatchdog and WithHardKill).
+//
+// The budget of a phase is the max timeout of its concurrent hooks, or in
+// PhaseStop the sum of the timeouts of the sequential hooks if larger.
+// Hooks without a timeout are not taken into account.
+//
+// Call it at startup, after hooks have been registered.
+func Validate() error { return std.Validate() }
+
+// Validate checks the registered hooks and the configured timeouts.
+// See the package-level Validate.
+func (m *Manager) Validate() error {
+	m.mu.Lock()
+	hs := append([]hook(nil), m.hooks...)
+	hs = append(hs, m.deferred...)
+	phs := map[Phase][]hook{}
+	for p, phHooks := range m.phaseHooks {
+		phs[p] = phHooks
+	}
+	m.mu.Unlock()
+
+	grace, watchdog, hardKill := m.graceTimeout, m.watchdog, m.hardKill
+
+	var errs []error
+
+	var total time.Duration
+	for _, p := range phases {
+		var budget time.Duration
+		for _, h := range phs[p] {
+			if t := h.effectiveTimeout(m); t > budget {
+				budget = t
+			}
+		}
+		if p == PhaseStop {
+			var seq time.Duration
+			for _, h := range hs {
+				seq += h.effectiveTimeout(m)
+			}
+			if seq > budget {
+				budget = seq
+			}
+		}
+		total += budget
+
+		if grace <= 0 {
+			continue
+		}
+		phHooks := phs[p]
+		if p == PhaseStop {
+			phHooks = append(append([]hook(nil), phHooks...), hs...)
+		}
+		for _, h := range phHooks {
+			if t := h.effectiveTimeout(m); t > grace {
+				errs = append(errs, fmt.Errorf("shutdown hook %v in phase %s: timeout %v exceeds grace timeout %v", h, p, t, grace))
+			}
+		}
+	}
+
+	if grace > 0 && total > grace {
+		errs = append(errs, fmt.Errorf("sum of phase budgets %v exceeds grace timeout %v", total, grace))
+	}
+	if grace > 0 && watchdog > 0 && watchdog < grace {
+		errs = append(errs, fmt.Errorf("watchdog timeout %v is shorter than grace timeout %v", watchdog, grace))
+	}
+	if grace > 0 && hardKill > 0 && hardKill < grace {
+		errs = append(errs, fmt.Errorf("hard kill timeout %v is shorter than grace timeout %v", hardKill, grace))
+	}
+	if watchdog > 0 && hardKill > 0 && hardKill < watchdog {
+		errs = append(errs, fmt.Errorf("hard kill timeout %v is shorter than watchdog timeout %v", hardKill, watchdog))
+	}
+
+	return errors.Join(errs...)
+}
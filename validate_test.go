@@ -0,0 +1,92 @@
+package shutdown
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	noop := func() {}
+	cases := []struct {
+		name  string
+		opts  []Option
+		setup func(m *Manager)
+		want  []string // Substrings of the error, nil if valid.
+	}{
+		{
+			name: "no grace timeout",
+			setup: func(m *Manager) {
+				m.OnShutdown(noop, WithTimeout(time.Hour))
+			},
+		},
+		{
+			name: "within budget",
+			opts: []Option{WithGraceTimeout(10 * time.Second), WithWatchdog(15 * time.Second), WithHardKill(20 * time.Second)},
+			setup: func(m *Manager) {
+				m.OnPhase(PhaseDrain, noop, WithTimeout(3*time.Second))
+				m.OnPhase(PhaseDrain, noop, WithTimeout(2*time.Second)) // Concurrent: max counts.
+				m.OnShutdown(noop, WithTimeout(2*time.Second))
+				m.Defer(noop, WithTimeout(2*time.Second))
+				m.OnShutdown(noop) // No timeout: not taken into account.
+			},
+		},
+		{
+			name: "sequential hooks exceed grace",
+			opts: []Option{WithGraceTimeout(5 * time.Second)},
+			setup: func(m *Manager) {
+				m.OnPhase(PhaseDrain, noop, WithTimeout(2*time.Second))
+				m.OnShutdown(noop, WithTimeout(2*time.Second))
+				m.Defer(noop, WithTimeout(2*time.Second))
+			},
+			want: []string{"sum of phase budgets 6s exceeds grace timeout 5s"},
+		},
+		{
+			name: "hook timeout exceeds grace",
+			opts: []Option{WithGraceTimeout(5 * time.Second)},
+			setup: func(m *Manager) {
+				m.OnPhase(PhaseCleanup, noop, WithName("flush"), WithTimeout(6*time.Second))
+			},
+			want: []string{"shutdown hook flush in phase cleanup: timeout 6s exceeds grace timeout 5s", "sum of phase budgets"},
+		},
+		{
+			name: "best effort timeout",
+			opts: []Option{WithGraceTimeout(5 * time.Second)},
+			setup: func(m *Manager) {
+				m.OnShutdown(noop, WithTimeout(time.Hour), WithBestEffort())
+			},
+		},
+		{
+			name: "timers fire early",
+			opts: []Option{WithGraceTimeout(10 * time.Second), WithWatchdog(5 * time.Second), WithHardKill(time.Second)},
+			want: []string{
+				"watchdog timeout 5s is shorter than grace timeout 10s",
+				"hard kill timeout 1s is shorter than grace timeout 10s",
+				"hard kill timeout 1s is shorter than watchdog timeout 5s",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		m := newTestManager(c.opts...)
+		if c.setup != nil {
+			c.setup(m)
+		}
+		err := m.Validate()
+		if c.want == nil {
+			if err != nil {
+				t.Errorf("%s: got %v, want no error", c.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: got no error", c.name)
+			continue
+		}
+		for _, w := range c.want {
+			if !strings.Contains(err.Error(), w) {
+				t.Errorf("%s: error %q does not contain %q", c.name, err, w)
+			}
+		}
+	}
+}
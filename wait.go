@@ -0,0 +1,204 @@
+package shutdown
+
+import "time"
+
+// Go runs f in a new goroutine registered in Wg, and returns true.
+//
+// Go is a safe alternative to calling Wg.Add(1) directly: once shutdown has
+// been initiated and the final Wait has been called (or once the final Wait
+// has returned), new goroutines can't be registered anymore. In that case
+// Go logs it and runs f immediately (in the caller's goroutine), and returns
+// false. This prevents the "WaitGroup is reused before previous Wait has
+// returned" panic, and if the caller is itself being waited for, f still
+// completes before the final Wait returns.
+//
+// Before shutdown is initiated, Go always starts a new goroutine, even if
+// the final Wait has already been called.
+func Go(f func()) bool { return std.Go(f) }
+
+// Go runs f in a new goroutine registered in Wg. See the package-level Go.
+func (m *Manager) Go(f func()) bool {
+	if !m.tryGo(f) {
+		m.logf("Final wait in progress, running task immediately...")
+		f()
+		return false
+	}
+	return true
+}
+
+// tryGo runs f in a new goroutine registered in Wg, and returns true. If
+// goroutines can't be registered anymore (see addRefused), it returns false
+// without running f.
+func (m *Manager) tryGo(f func()) bool {
+	site := callSite()
+
+	m.mu.Lock()
+	if m.addRefused() {
+		m.mu.Unlock()
+		return false
+	}
+	m.Wg.Add(1)
+	m.tasks++
+	m.taskSites[site]++
+	m.mu.Unlock()
+
+	go func() {
+		defer m.taskDone(site)
+		f()
+	}()
+	return true
+}
+
+// addRefused tells if goroutines can't be registered in Wg anymore: shutdown
+// has been initiated and the final Wait has been called (so Wg may be waited
+// for with a zero counter once the hook runner completes), or the final Wait
+// has returned. m.mu must be held.
+func (m *Manager) addRefused() bool {
+	select {
+	case <-m.waitDone:
+		return true
+	default:
+	}
+	return m.waiting && len(m.causes) > 0
+}
+
+// taskDone marks a goroutine started by Go at site as done, recording its
+// drain time if Context has been cancelled.
+func (m *Manager) taskDone(site string) {
+	m.mu.Lock()
+	m.tasks--
+	if m.taskSites[site]--; m.taskSites[site] == 0 {
+		delete(m.taskSites, site)
+	}
+	if !m.cancelledAt.IsZero() {
+		m.drainTimes = append(m.drainTimes, DrainTime{Name: site, Duration: time.Since(m.cancelledAt)})
+	}
+	m.mu.Unlock()
+	m.Wg.Done()
+}
+
+// Wait is the final wait: it waits for Wg (all registered goroutines) and for
+// open critical sections (see Enter), then shuts down servers registered with ManageLast.
+// Call it in main() before returning, instead of calling Wg.Wait() directly.
+//
+// Wait first waits for shutdown to be initiated, so it never completes
+// the shutdown before the hooks have been run. An app that finishes its work
+// on its own should initiate the shutdown (e.g. with InitiateManual) before
+// calling Wait.
+//
+// Once shutdown has been initiated and Wait has been called, Go does not start
+// new goroutines (see Go).
+// Wait may be called multiple times (e.g. when auto exit is enabled, see
+// WithAutoExit), the final wait is performed only once, subsequent calls
+// wait for its completion.
+func Wait() { std.Wait() }
+
+// Wait is the final wait. See the package-level Wait.
+func (m *Manager) Wait() {
+	m.waitOnce.Do(func() {
+		m.mu.Lock()
+		m.waiting = true
+		m.mu.Unlock()
+
+		<-m.C
+		m.waitWg()
+		m.waitGuards()
+
+		m.shutdownLastServers()
+
+		m.mu.Lock()
+		if m.hardKillTimer != nil {
+			m.hardKillTimer.Stop()
+		}
+		m.mu.Unlock()
+
+		m.mu.Lock()
+		m.completed = true
+		close(m.waitDone)
+		m.mu.Unlock()
+		m.noticef("Shutdown completed in %v.", m.Duration().Round(time.Millisecond))
+		m.logDrainTimes()
+		m.updateStatusFile()
+		m.writeReport()
+	})
+}
+
+// Completed returns a channel that is closed when the final Wait has completed:
+// all hooks and goroutines registered in Wg have returned, critical sections
+// have been left and the servers registered with ManageLast have been shut down.
+//
+// Unlike C, which is closed when shutdown is initiated, Completed lets auxiliary
+// goroutines (e.g. a supervisor thread of a cgo host) wait for the shutdown to
+// actually complete. If the grace timeout is exceeded, the channel is closed when
+// the final Wait stops waiting.
+//
+// The channel is closed by the final Wait: if the app never calls Wait
+// (directly, or through Exit or auto exit, see WithAutoExit), it is never closed.
+func Completed() <-chan struct{} { return std.Completed() }
+
+// Completed returns a channel that is closed when the final Wait has completed.
+// See the package-level Completed.
+func (m *Manager) Completed() <-chan struct{} {
+	return m.waitDone
+}
+
+// Close initiates a shutdown (with the cause "close", see Reason) if it has not
+// been initiated yet, performs the final Wait, and then releases the OS signal
+// subscriptions of m (shutdown, reload and diagnostics signals), so m doesn't
+// react to signals anymore, and leaves no goroutines behind. It returns the
+// errors of the shutdown hooks (see HooksErr).
+//
+// Close implements io.Closer, so a Manager may be passed to APIs closing their
+// resources when done (e.g. tests using t.Cleanup). Pass m.Context to APIs
+// expecting a context.Context.
+func (m *Manager) Close() error {
+	if m.startInitiation("close") {
+		m.noticef("Shutdown initiated by Close...")
+		m.broadcast(&Cause{Text: "close"})
+	}
+	m.Wait()
+
+	m.closeOnce.Do(func() {
+		// Make sure reload can't be enabled anymore.
+		m.reloadOnce.Do(func() {})
+
+		if m.sigSrc != nil {
+			m.sigSrc.unsubscribe()
+		}
+		if m.reloadSub != nil {
+			close(m.reloadSub.stop)
+		}
+	})
+
+	return m.HooksErr()
+}
+
+// waitWg waits for m.Wg, respecting the grace timeout and escalation.
+// It must be called after shutdown has been initiated.
+func (m *Manager) waitWg() {
+	done := make(chan struct{})
+	go func() {
+		m.Wg.Wait()
+		close(done)
+	}()
+
+	var graceC <-chan struct{}
+	if m.graceTimeout > 0 {
+		graceC = m.deadline(m.graceTimeout)
+	}
+
+	select {
+	case <-done:
+		return
+	case <-graceC:
+	case <-m.hurry:
+		m.warnf("Shutdown escalated, waiting only for critical hooks...")
+		select {
+		case <-m.hooksDone:
+			return
+		case <-graceC:
+		}
+	}
+	m.warnf("Shutdown grace timeout (%v) exceeded, not waiting anymore.", m.graceTimeout)
+	m.beginExit()
+}
@@ -0,0 +1,136 @@
+package shutdown
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGo(t *testing.T) {
+	m := newTestManager()
+	done := make(chan struct{})
+	if !m.Go(func() {
+		<-m.C
+		time.Sleep(10 * time.Millisecond)
+		close(done)
+	}) {
+		t.Error("Go returned false before the final Wait")
+	}
+
+	m.Close()
+
+	select {
+	case <-done:
+	default:
+		t.Error("final Wait returned before the goroutine started by Go")
+	}
+
+	// After the final Wait, f is run in the caller's goroutine.
+	ran := false
+	if m.Go(func() { ran = true }) {
+		t.Error("Go returned true after the final Wait")
+	}
+	if !ran {
+		t.Error("f was not run after the final Wait")
+	}
+}
+
+func TestGoDuringWait(t *testing.T) {
+	m := newTestManager()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	m.OnShutdown(func() {
+		close(started)
+		<-release
+	})
+
+	m.InitiateManual()
+	<-started
+
+	waitDone := make(chan struct{})
+	go func() {
+		m.Wait()
+		close(waitDone)
+	}()
+
+	waitUntilWaiting(m)
+
+	// Go must not register in Wg while the final Wait is waiting for it.
+	ran := false
+	if m.Go(func() { ran = true }) || !ran {
+		t.Errorf("Go during the final Wait returned true or did not run f (ran: %t)", ran)
+	}
+
+	close(release)
+	<-waitDone
+}
+
+// waitUntilWaiting waits until the final Wait of m has been called.
+func waitUntilWaiting(m *Manager) {
+	for {
+		m.mu.Lock()
+		waiting := m.waiting
+		m.mu.Unlock()
+		if waiting {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGoWaitBeforeInitiation(t *testing.T) {
+	m := newTestManager()
+	m.Go(func() { <-m.C })
+
+	waitDone := make(chan struct{})
+	go func() {
+		m.Wait()
+		close(waitDone)
+	}()
+	waitUntilWaiting(m)
+
+	release := make(chan struct{})
+	start := time.Now()
+	if !m.Go(func() { <-release }) {
+		t.Error("Go returned false before initiation")
+	}
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Errorf("Go blocked its caller for %v before initiation", d)
+	}
+
+	m.InitiateManual()
+	close(release)
+	<-waitDone
+}
+
+func TestWaitBeforeInitiation(t *testing.T) {
+	m := newTestManager()
+	hookRan := make(chan struct{})
+	m.OnShutdown(func() { close(hookRan) })
+	m.Go(func() {})
+
+	waitDone := make(chan struct{})
+	go func() {
+		m.Wait()
+		close(waitDone)
+	}()
+
+	select {
+	case <-waitDone:
+		t.Fatal("Wait returned before initiation")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if !m.Go(func() {}) {
+		t.Error("Go refused before initiation")
+	}
+
+	m.InitiateManual()
+	<-waitDone
+	select {
+	case <-hookRan:
+	default:
+		t.Error("Wait returned before the hooks")
+	}
+	if got := m.Status().State; got != StateCompleted {
+		t.Errorf("State is %v, want %v", got, StateCompleted)
+	}
+}
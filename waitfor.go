@@ -0,0 +1,60 @@
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WaitFor polls cond with the given interval until it reports true,
+// in which case nil is returned.
+// It aborts with ErrInitiated if shutdown is initiated, and with ctx.Err()
+// if ctx is done.
+//
+// cond is first checked immediately.
+//
+// WaitFor panics if interval is not positive.
+func WaitFor(ctx context.Context, interval time.Duration, cond func() bool) error {
+	return std.WaitFor(ctx, interval, cond)
+}
+
+// WaitFor polls cond with the given interval until it reports true,
+// aborting on shutdown. See the package-level WaitFor.
+func (m *Manager) WaitFor(ctx context.Context, interval time.Duration, cond func() bool) error {
+	return waitFor(ctx, m.C, interval, cond)
+}
+
+// waitFor polls cond with the given interval until it reports true.
+// It aborts with ErrInitiated if abortCh is closed (if non-nil),
+// and with ctx.Err() if ctx is done.
+func waitFor(ctx context.Context, abortCh <-chan struct{}, interval time.Duration, cond func() bool) error {
+	checkInterval(interval)
+
+	if cond() {
+		return nil
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if cond() {
+				return nil
+			}
+		case <-abortCh:
+			return ErrInitiated
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// checkInterval panics if the polling interval is not positive
+// (which would make time.NewTicker panic with a less helpful message).
+func checkInterval(interval time.Duration) {
+	if interval <= 0 {
+		panic(fmt.Sprintf("shutdown: non-positive polling interval %v", interval))
+	}
+}
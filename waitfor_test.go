@@ -0,0 +1,38 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitFor(t *testing.T) {
+	m := newTestManager()
+	n := 0
+	if err := m.WaitFor(context.Background(), time.Millisecond, func() bool { n++; return n == 3 }); err != nil {
+		t.Errorf("WaitFor returned %v", err)
+	}
+
+	m.InitiateManual()
+	if err := m.WaitFor(context.Background(), time.Millisecond, func() bool { return false }); !errors.Is(err, ErrInitiated) {
+		t.Errorf("WaitFor returned %v, want %v", err, ErrInitiated)
+	}
+}
+
+func TestNonPositiveInterval(t *testing.T) {
+	m := newTestManager()
+	for name, f := range map[string]func(){
+		"WaitFor": func() { m.WaitFor(context.Background(), 0, func() bool { return true }) },
+		"Poll":    func() { m.Poll(-time.Second, func(ctx context.Context) {}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,83 @@
+package shutdown
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Watch handles events of a watcher (e.g. an fsnotify.Watcher) in a new
+// goroutine, calling handle for each event received from events.
+//
+// The watcher is stopped by a hook of PhaseDrain (so it is ordered with the
+// hooks of the other phases): closer (the watcher) is closed, and events still
+// queued are handled until events is closed. closer must close the events
+// channel when closed (as fsnotify.Watcher.Close does). The events channel is
+// never closed by Watch, so the watcher's senders can't hit a "send on closed
+// channel" panic. If Watch is called after shutdown has been initiated, the
+// watcher is stopped immediately.
+func Watch[T any](events <-chan T, closer io.Closer, handle func(T)) {
+	WatchOn(std, events, closer, handle)
+}
+
+// WatchOn is like Watch, but it operates on the shutdown managed by m.
+func WatchOn[T any](m *Manager, events <-chan T, closer io.Closer, handle func(T)) {
+	done := make(chan struct{})
+	m.Go(func() {
+		defer close(done)
+		for ev := range events {
+			handle(ev)
+		}
+	})
+
+	stop := func() {
+		closer.Close()
+		<-done // Wait for queued events to be handled.
+	}
+	if m.Initiated() {
+		m.Go(stop)
+		return
+	}
+	m.OnPhase(PhaseDrain, stop, WithName("watcher"))
+}
+
+// Poll calls poll in a new goroutine immediately and then periodically with
+// the given interval. Context is passed to poll.
+//
+// Polling is stopped by a hook of PhaseDrain (so it is ordered with the hooks
+// of the other phases), which waits for a poll in progress to return. If Poll
+// is called after shutdown has been initiated, poll is not called.
+//
+// Poll panics if interval is not positive.
+func Poll(interval time.Duration, poll func(ctx context.Context)) { std.Poll(interval, poll) }
+
+// Poll calls poll in a new goroutine immediately and then periodically with
+// the given interval. See the package-level Poll.
+func (m *Manager) Poll(interval time.Duration, poll func(ctx context.Context)) {
+	checkInterval(interval)
+	if m.Initiated() {
+		return
+	}
+
+	stopCh, done := make(chan struct{}), make(chan struct{})
+	m.Go(func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			poll(m.Context)
+
+			select {
+			case <-ticker.C:
+			case <-stopCh:
+				return
+			}
+		}
+	})
+
+	m.OnPhase(PhaseDrain, func() {
+		close(stopCh)
+		<-done
+	}, WithName("poller"))
+}
@@ -0,0 +1,114 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeWatcher is a watcher closing its events channel when closed.
+type fakeWatcher struct {
+	events chan int
+	once   sync.Once
+}
+
+func (w *fakeWatcher) Close() error {
+	w.once.Do(func() { close(w.events) })
+	return nil
+}
+
+func TestWatch(t *testing.T) {
+	m := newTestManager()
+	w := &fakeWatcher{events: make(chan int, 10)}
+
+	var mu sync.Mutex
+	var handled []int
+	var stopped bool
+	WatchOn(m, w.events, w, func(ev int) {
+		mu.Lock()
+		handled = append(handled, ev)
+		mu.Unlock()
+	})
+	m.OnPhase(PhaseStop, func() {
+		mu.Lock()
+		stopped = len(handled) == 3
+		mu.Unlock()
+	})
+
+	w.events <- 1
+	w.events <- 2
+	w.events <- 3 // Queued events are handled on shutdown.
+	m.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 3 {
+		t.Errorf("handled %v, want 3 events", handled)
+	}
+	if !stopped {
+		t.Error("watcher not drained before PhaseStop")
+	}
+}
+
+func TestWatchAfterInitiation(t *testing.T) {
+	m := newTestManager()
+	m.InitiateManual()
+	<-m.C
+
+	w := &fakeWatcher{events: make(chan int, 10)}
+	WatchOn(m, w.events, w, func(int) {})
+	m.Wait()
+
+	select {
+	case _, ok := <-w.events:
+		if ok {
+			t.Error("unexpected event")
+		}
+	default:
+		t.Error("watcher not closed")
+	}
+}
+
+func TestPoll(t *testing.T) {
+	m := newTestManager()
+	var polls, polling int32
+	m.Poll(5*time.Millisecond, func(ctx context.Context) {
+		atomic.AddInt32(&polls, 1)
+		atomic.StoreInt32(&polling, 1)
+		time.Sleep(20 * time.Millisecond) // Poll in progress on shutdown.
+		atomic.StoreInt32(&polling, 0)
+	})
+	stoppedInDrain := false
+	m.OnPhaseEnd(PhaseDrain, func() {
+		stoppedInDrain = atomic.LoadInt32(&polling) == 0
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	m.Close()
+	if !stoppedInDrain {
+		t.Error("poll in progress at the end of PhaseDrain")
+	}
+
+	n := atomic.LoadInt32(&polls)
+	if n == 0 {
+		t.Error("poll was not called")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if n2 := atomic.LoadInt32(&polls); n2 != n {
+		t.Errorf("poll called %d times after shutdown", n2-n)
+	}
+
+	// Poll after initiation does not call poll.
+	m.Poll(time.Millisecond, func(ctx context.Context) { t.Error("poll called after initiation") })
+}
+
+func TestPollInvalidInterval(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Poll did not panic for a non-positive interval")
+		}
+	}()
+	newTestManager().Poll(0, func(ctx context.Context) {})
+}